@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/csv"
 	"flag"
 	"fmt"
@@ -12,10 +13,17 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/codingconcepts/dg/internal/pkg/bootstrap"
+	"github.com/codingconcepts/dg/internal/pkg/diagram"
+	"github.com/codingconcepts/dg/internal/pkg/explain"
 	"github.com/codingconcepts/dg/internal/pkg/generator"
 	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/codingconcepts/dg/internal/pkg/random"
+	"github.com/codingconcepts/dg/internal/pkg/schema"
 	"github.com/codingconcepts/dg/internal/pkg/source"
 	"github.com/codingconcepts/dg/internal/pkg/ui"
+	"github.com/codingconcepts/dg/internal/pkg/unique"
 	"github.com/codingconcepts/dg/internal/pkg/web"
 	"github.com/samber/lo"
 )
@@ -27,12 +35,53 @@ var (
 func main() {
 	log.SetFlags(0)
 
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "schema":
+			if err := runSchema(os.Args[2:]); err != nil {
+				log.Fatalf("error generating schema: %v", err)
+			}
+			return
+
+		case "init":
+			if err := runInit(os.Args[2:]); err != nil {
+				log.Fatalf("error creating config: %v", err)
+			}
+			return
+
+		case "infer":
+			if err := runInfer(os.Args[2:]); err != nil {
+				log.Fatalf("error inferring config: %v", err)
+			}
+			return
+
+		case "explain":
+			if err := runExplain(os.Args[2:]); err != nil {
+				log.Fatalf("error explaining config: %v", err)
+			}
+			return
+
+		case "preview":
+			if err := runPreview(os.Args[2:]); err != nil {
+				log.Fatalf("error previewing config: %v", err)
+			}
+			return
+
+		case "diagram":
+			if err := runDiagram(os.Args[2:]); err != nil {
+				log.Fatalf("error creating diagram: %v", err)
+			}
+			return
+		}
+	}
+
 	configPath := flag.String("c", "", "the absolute or relative path to the config file")
 	outputDir := flag.String("o", ".", "the absolute or relative path to the output dir")
 	createImports := flag.String("i", "", "write import statements to file")
 	cpuprofile := flag.String("cpuprofile", "", "write cpu profile to file")
 	versionFlag := flag.Bool("version", false, "display the current version number")
 	port := flag.Int("p", 0, "port to serve files from (omit to generate without serving)")
+	seed := flag.Int64("seed", 0, "seed the random number generators to make generation repeatable (omit to generate different data each time)")
 	flag.Parse()
 
 	if *cpuprofile != "" {
@@ -49,6 +98,10 @@ func main() {
 		os.Exit(2)
 	}
 
+	if *seed != 0 {
+		seedRandom(*seed)
+	}
+
 	tt := ui.TimeTracker(os.Stdout, realClock{}, 40)
 	defer tt(time.Now(), "done")
 
@@ -63,7 +116,7 @@ func main() {
 		log.Fatalf("error loading inputs: %v", err)
 	}
 
-	if err = generateTables(c, tt, files); err != nil {
+	if err = generateTables(c, tt, files, 0); err != nil {
 		log.Fatalf("error generating tables: %v", err)
 	}
 
@@ -88,6 +141,250 @@ func main() {
 	log.Fatal(web.Serve(*outputDir, *port))
 }
 
+func runSchema(args []string) error {
+	fs := flag.NewFlagSet("schema", flag.ExitOnError)
+	outputPath := fs.String("o", "", "write the schema to a file instead of stdout")
+	fs.Parse(args)
+
+	s, err := schema.Generate()
+	if err != nil {
+		return fmt.Errorf("building schema: %w", err)
+	}
+
+	if *outputPath == "" {
+		fmt.Println(string(s))
+		return nil
+	}
+
+	return os.WriteFile(*outputPath, append(s, '\n'), 0644)
+}
+
+func runInit(args []string) error {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	ddlPath := fs.String("from-ddl", "", "the absolute or relative path to a file of CREATE TABLE statements")
+	dbURL := fs.String("from-db", "", "the connection string of a Postgres or CockroachDB database to introspect")
+	dbSchema := fs.String("schema", "public", "the database schema to introspect")
+	outputPath := fs.String("o", "", "write the config to a file instead of stdout")
+	count := fs.Int("count", 100, "the number of rows to generate for each table (for tables without a row count)")
+	fs.Parse(args)
+
+	var tables []bootstrap.Table
+	var err error
+
+	switch {
+	case *ddlPath != "":
+		if tables, err = loadDDL(*ddlPath); err != nil {
+			return fmt.Errorf("loading ddl: %w", err)
+		}
+
+	case *dbURL != "":
+		if tables, err = bootstrap.Introspect(context.Background(), *dbURL, *dbSchema); err != nil {
+			return fmt.Errorf("introspecting database: %w", err)
+		}
+
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	return writeConfig(*outputPath, tables, *count)
+}
+
+func runDiagram(args []string) error {
+	fs := flag.NewFlagSet("diagram", flag.ExitOnError)
+	configPath := fs.String("c", "", "the absolute or relative path to the config file")
+	format := fs.String("format", "mermaid", fmt.Sprintf("the diagram format (%s)", strings.Join(diagram.Formats, ", ")))
+	outputPath := fs.String("o", "", "write the diagram to a file instead of stdout")
+	fs.Parse(args)
+
+	if *configPath == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	c, err := loadConfig(*configPath, noopTimer)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	g, err := diagram.Build(c)
+	if err != nil {
+		return fmt.Errorf("building diagram: %w", err)
+	}
+
+	if *outputPath == "" {
+		return g.Write(os.Stdout, *format)
+	}
+
+	file, err := os.Create(*outputPath)
+	if err != nil {
+		return fmt.Errorf("creating diagram file: %w", err)
+	}
+	defer file.Close()
+
+	return g.Write(file, *format)
+}
+
+func runPreview(args []string) error {
+	fs := flag.NewFlagSet("preview", flag.ExitOnError)
+	configPath := fs.String("c", "", "the absolute or relative path to the config file")
+	rows := fs.Int("n", 10, "the maximum number of rows to generate for each table")
+	tableName := fs.String("t", "", "only print the table with this name")
+	seed := fs.Int64("seed", 0, "seed the random number generators to make generation repeatable")
+	fs.Parse(args)
+
+	if *configPath == "" || *rows <= 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if *seed != 0 {
+		seedRandom(*seed)
+	}
+
+	c, err := loadConfig(*configPath, noopTimer)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	if *tableName != "" && !lo.ContainsBy(c.Tables, func(t model.Table) bool { return t.Name == *tableName }) {
+		return fmt.Errorf("missing table %q", *tableName)
+	}
+
+	files := make(map[string]model.CSVFile)
+	if err = loadInputs(c, path.Dir(*configPath), noopTimer, files); err != nil {
+		return fmt.Errorf("loading inputs: %w", err)
+	}
+
+	if err = generateTables(c, noopTimer, files, *rows); err != nil {
+		return fmt.Errorf("generating tables: %w", err)
+	}
+
+	if err = removeSuppressedColumns(c, noopTimer, files); err != nil {
+		return fmt.Errorf("removing supressed columns: %w", err)
+	}
+
+	for _, table := range c.Tables {
+		file := files[table.Name]
+
+		switch {
+		case *tableName != "" && table.Name != *tableName:
+			continue
+		case *tableName == "" && !file.Output:
+			continue
+		}
+
+		fmt.Printf("%s (%d rows)\n", table.Name, file.RowCount())
+		if err = ui.WriteTable(os.Stdout, file.Header, generator.Transpose(file.Lines), 40); err != nil {
+			return fmt.Errorf("writing table %q: %w", table.Name, err)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func runExplain(args []string) error {
+	fs := flag.NewFlagSet("explain", flag.ExitOnError)
+	configPath := fs.String("c", "", "the absolute or relative path to the config file")
+	maxRows := fs.Int("max-rows", 10000000, "warn if any table will have more rows than this (0 to disable)")
+	maxMemory := fs.String("max-memory", "4GB", "warn if generation will use more memory than this (0 to disable)")
+	maxOutput := fs.String("max-output", "10GB", "warn if the csv files will be larger than this (0 to disable)")
+	fs.Parse(args)
+
+	if *configPath == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	th := explain.Thresholds{Rows: *maxRows}
+	var err error
+	if th.Memory, err = explain.ParseBytes(*maxMemory); err != nil {
+		return fmt.Errorf("parsing max memory: %w", err)
+	}
+	if th.Output, err = explain.ParseBytes(*maxOutput); err != nil {
+		return fmt.Errorf("parsing max output: %w", err)
+	}
+
+	c, err := loadConfig(*configPath, noopTimer)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	files := make(map[string]model.CSVFile)
+	if err = loadInputs(c, path.Dir(*configPath), noopTimer, files); err != nil {
+		return fmt.Errorf("loading inputs: %w", err)
+	}
+
+	plan, err := explain.Explain(c, files, th)
+	if err != nil {
+		return err
+	}
+
+	return plan.Write(os.Stdout)
+}
+
+func runInfer(args []string) error {
+	fs := flag.NewFlagSet("infer", flag.ExitOnError)
+	outputPath := fs.String("o", "", "write the config to a file instead of stdout")
+	maxSetSize := fs.Int("max-set", 20, "the maximum number of distinct values a column can have to be generated from a set")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: dg infer [flags] FILE.csv...")
+		fs.PrintDefaults()
+		os.Exit(2)
+	}
+
+	var samples []bootstrap.Sample
+	for _, filename := range fs.Args() {
+		file, err := os.Open(filename)
+		if err != nil {
+			return fmt.Errorf("opening file: %w", err)
+		}
+		defer file.Close()
+
+		name := strings.TrimSuffix(path.Base(filename), path.Ext(filename))
+		samples = append(samples, bootstrap.Sample{Name: name, Data: file})
+	}
+
+	if *outputPath == "" {
+		return bootstrap.InferConfig(os.Stdout, samples, *maxSetSize)
+	}
+
+	file, err := os.Create(*outputPath)
+	if err != nil {
+		return fmt.Errorf("creating config file: %w", err)
+	}
+	defer file.Close()
+
+	return bootstrap.InferConfig(file, samples, *maxSetSize)
+}
+
+func loadDDL(filename string) ([]bootstrap.Table, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("opening file: %w", err)
+	}
+	defer file.Close()
+
+	return bootstrap.ParseDDL(file)
+}
+
+func writeConfig(outputPath string, tables []bootstrap.Table, count int) error {
+	if outputPath == "" {
+		return bootstrap.WriteConfig(os.Stdout, tables, count)
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("creating config file: %w", err)
+	}
+	defer file.Close()
+
+	return bootstrap.WriteConfig(file, tables, count)
+}
+
 func loadConfig(filename string, tt ui.TimerFunc) (model.Config, error) {
 	defer tt(time.Now(), "loaded config file")
 
@@ -97,7 +394,18 @@ func loadConfig(filename string, tt ui.TimerFunc) (model.Config, error) {
 	}
 	defer file.Close()
 
-	return model.LoadConfig(file)
+	c, err := model.LoadConfig(file)
+	if err != nil {
+		return model.Config{}, err
+	}
+
+	// Order the tables, so that any table is generated after the tables
+	// that it depends on.
+	if c.Tables, err = model.SortTables(c.Tables); err != nil {
+		return model.Config{}, fmt.Errorf("ordering tables: %w", err)
+	}
+
+	return c, nil
 }
 
 func loadInputs(c model.Config, configDir string, tt ui.TimerFunc, files map[string]model.CSVFile) error {
@@ -130,11 +438,22 @@ func loadInput(input model.Input, configDir string, tt ui.TimerFunc, files map[s
 	return nil
 }
 
-func generateTables(c model.Config, tt ui.TimerFunc, files map[string]model.CSVFile) error {
+// generateTables generates each of the config's tables. If limit is greater
+// than zero, no table will have more than limit rows.
+func generateTables(c model.Config, tt ui.TimerFunc, files map[string]model.CSVFile, limit int) error {
 	defer tt(time.Now(), "generated all tables")
 
 	for _, table := range c.Tables {
-		if err := generateTable(table, files, tt); err != nil {
+		count, err := table.EvaluateCount(model.RowCounts(files))
+		if err != nil {
+			return fmt.Errorf("evaluating count for %q: %w", table.Name, err)
+		}
+		table.Count = count
+		if limit > 0 && table.Count > limit {
+			table.Count = limit
+		}
+
+		if err := generateTable(table, files, tt, limit); err != nil {
 			return fmt.Errorf("generating csv file for %q: %w", table.Name, err)
 		}
 	}
@@ -142,7 +461,7 @@ func generateTables(c model.Config, tt ui.TimerFunc, files map[string]model.CSVF
 	return nil
 }
 
-func generateTable(t model.Table, files map[string]model.CSVFile, tt ui.TimerFunc) error {
+func generateTable(t model.Table, files map[string]model.CSVFile, tt ui.TimerFunc, limit int) error {
 	defer tt(time.Now(), fmt.Sprintf("generated table: %s", t.Name))
 
 	// Create the Cartesian product of any each types first.
@@ -150,6 +469,7 @@ func generateTable(t model.Table, files map[string]model.CSVFile, tt ui.TimerFun
 	if err := eg.Generate(t, files); err != nil {
 		return fmt.Errorf("generating each columns: %w", err)
 	}
+	limitRows(t.Name, files, limit)
 
 	// Create any const columns next.
 	var cg generator.ConstGenerator
@@ -158,6 +478,60 @@ func generateTable(t model.Table, files map[string]model.CSVFile, tt ui.TimerFun
 	}
 
 	for _, col := range t.Columns {
+		if col.Type == "each" || col.Type == "const" {
+			if err := transformColumn(t, col, files); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := generateColumns(t, files, func(model.Column) bool { return true }); err != nil {
+		return err
+	}
+
+	file, ok := files[t.Name]
+	if !ok {
+		return fmt.Errorf("missing table: %q", t.Name)
+	}
+
+	if len(file.UniqueColumns) > 0 {
+		switch t.UniqueMode {
+		case "", "drop":
+			if discarded := unique.Drop(t, files); discarded > 0 {
+				log.Printf("discarded %d duplicate rows from %q", discarded, t.Name)
+			}
+
+		case "regenerate":
+			replaced, discarded, err := unique.Regenerate(t, files, generateColumns)
+			if err != nil {
+				return fmt.Errorf("regenerating duplicate rows: %w", err)
+			}
+
+			if discarded > 0 {
+				log.Printf("replaced %d duplicate rows in %q (discarding %d duplicates in total)", replaced, t.Name, discarded)
+			}
+
+		default:
+			return fmt.Errorf("%q is not a valid unique_mode", t.UniqueMode)
+		}
+	}
+
+	// Columns whose length isn't determined by the table's count (e.g.
+	// ranges with a step) may still exceed the limit.
+	limitRows(t.Name, files, limit)
+
+	return nil
+}
+
+// generateColumns runs the generators of a table's columns (other than each
+// and const columns, which are generated up-front), in the order they appear
+// in the config, skipping any columns that include returns false for.
+func generateColumns(t model.Table, files map[string]model.CSVFile, include func(model.Column) bool) error {
+	for _, col := range t.Columns {
+		if !include(col) {
+			continue
+		}
+
 		switch col.Type {
 		case "ref":
 			var g generator.RefGenerator
@@ -221,24 +595,121 @@ func generateTable(t model.Table, files map[string]model.CSVFile, tt ui.TimerFun
 			if err := g.Generate(t, col, files); err != nil {
 				return fmt.Errorf("running match process for %s.%s: %w", t.Name, col.Name, err)
 			}
+
+		case "expr":
+			var g generator.ExprGenerator
+			if err := col.Generator.UnmarshalFunc(&g); err != nil {
+				return fmt.Errorf("parsing expr process for %s.%s: %w", t.Name, col.Name, err)
+			}
+			if err := g.Generate(t, col, files); err != nil {
+				return fmt.Errorf("running expr process for %s.%s: %w", t.Name, col.Name, err)
+			}
+
+		case "dist":
+			var g generator.DistGenerator
+			if err := col.Generator.UnmarshalFunc(&g); err != nil {
+				return fmt.Errorf("parsing dist process for %s.%s: %w", t.Name, col.Name, err)
+			}
+			if err := g.Generate(t, col, files); err != nil {
+				return fmt.Errorf("running dist process for %s.%s: %w", t.Name, col.Name, err)
+			}
+
+		case "tree":
+			var g generator.TreeGenerator
+			if err := col.Generator.UnmarshalFunc(&g); err != nil {
+				return fmt.Errorf("parsing tree process for %s.%s: %w", t.Name, col.Name, err)
+			}
+			if err := g.Generate(t, col, files); err != nil {
+				return fmt.Errorf("running tree process for %s.%s: %w", t.Name, col.Name, err)
+			}
+
+		case "walk":
+			var g generator.WalkGenerator
+			if err := col.Generator.UnmarshalFunc(&g); err != nil {
+				return fmt.Errorf("parsing walk process for %s.%s: %w", t.Name, col.Name, err)
+			}
+			if err := g.Generate(t, col, files); err != nil {
+				return fmt.Errorf("running walk process for %s.%s: %w", t.Name, col.Name, err)
+			}
+
+		case "timeseries":
+			var g generator.TimeseriesGenerator
+			if err := col.Generator.UnmarshalFunc(&g); err != nil {
+				return fmt.Errorf("parsing timeseries process for %s.%s: %w", t.Name, col.Name, err)
+			}
+			if err := g.Generate(t, col, files); err != nil {
+				return fmt.Errorf("running timeseries process for %s.%s: %w", t.Name, col.Name, err)
+			}
+
+		case "case":
+			var g generator.CaseGenerator
+			if err := col.Generator.UnmarshalFunc(&g); err != nil {
+				return fmt.Errorf("parsing case process for %s.%s: %w", t.Name, col.Name, err)
+			}
+			if err := g.Generate(t, col, files, generateColumn); err != nil {
+				return fmt.Errorf("running case process for %s.%s: %w", t.Name, col.Name, err)
+			}
+
+		case "template":
+			var g generator.TemplateGenerator
+			if err := col.Generator.UnmarshalFunc(&g); err != nil {
+				return fmt.Errorf("parsing template process for %s.%s: %w", t.Name, col.Name, err)
+			}
+			if err := g.Generate(t, col, files); err != nil {
+				return fmt.Errorf("running template process for %s.%s: %w", t.Name, col.Name, err)
+			}
+		}
+
+		// Each and const columns are transformed when they're generated.
+		if col.Type != "each" && col.Type != "const" {
+			if err := transformColumn(t, col, files); err != nil {
+				return err
+			}
 		}
 	}
 
-	file, ok := files[t.Name]
-	if !ok {
-		return fmt.Errorf("missing table: %q", t.Name)
+	return nil
+}
+
+// generateColumn generates a single column, for generators that embed the
+// processors of other column types.
+func generateColumn(t model.Table, col model.Column, files map[string]model.CSVFile) error {
+	t.Columns = []model.Column{col}
+
+	switch col.Type {
+	case "each":
+		return fmt.Errorf("each columns can't be embedded in other columns")
+
+	case "const":
+		var cg generator.ConstGenerator
+		return cg.Generate(t, files)
+
+	default:
+		return generateColumns(t, files, func(model.Column) bool { return true })
 	}
+}
 
-	if len(file.UniqueColumns) > 0 {
-		file.Lines = generator.Transpose(file.Lines)
-		file.Lines = file.Unique()
-		file.Lines = generator.Transpose(file.Lines)
+func transformColumn(t model.Table, col model.Column, files map[string]model.CSVFile) error {
+	if err := generator.Transform(t, col, files); err != nil {
+		return fmt.Errorf("transforming %s.%s: %w", t.Name, col.Name, err)
 	}
-	files[t.Name] = file
 
 	return nil
 }
 
+func limitRows(table string, files map[string]model.CSVFile, limit int) {
+	file, ok := files[table]
+	if !ok || limit <= 0 {
+		return
+	}
+
+	for i, line := range file.Lines {
+		if len(line) > limit {
+			file.Lines[i] = line[:limit]
+		}
+	}
+}
+
 func removeSuppressedColumns(c model.Config, tt ui.TimerFunc, files map[string]model.CSVFile) error {
 	defer tt(time.Now(), "removed suppressed columns")
 
@@ -377,3 +848,14 @@ func (realClock) Now() time.Time {
 func (realClock) Since(t time.Time) time.Duration {
 	return time.Since(t)
 }
+
+// seedRandom makes the values generated by dg and its placeholders
+// repeatable for a given seed.
+func seedRandom(seed int64) {
+	random.Seed(seed)
+	gofakeit.Seed(seed)
+}
+
+// noopTimer discards timings for commands whose output shouldn't be
+// interleaved with them.
+func noopTimer(time.Time, string) {}
@@ -0,0 +1,247 @@
+package bootstrap
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/codingconcepts/dg/internal/pkg/generator"
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	dateFormat      = "2006-01-02"
+	timestampFormat = "2006-01-02T15:04:05Z07:00"
+	dateFrom        = "2020-01-01T00:00:00Z"
+	dateTo          = "2025-01-01T00:00:00Z"
+	maxTextLength   = 32
+)
+
+// configFile, tableConfig, columnConfig, and transformConfig mirror the model
+// types but can be written to YAML.
+type configFile struct {
+	Tables []tableConfig `yaml:"tables"`
+}
+
+type tableConfig struct {
+	Name          string         `yaml:"name"`
+	Count         int            `yaml:"count,omitempty"`
+	UniqueColumns []string       `yaml:"unique_columns,omitempty,flow"`
+	Columns       []columnConfig `yaml:"columns"`
+}
+
+type columnConfig struct {
+	Name      string           `yaml:"name"`
+	Type      string           `yaml:"type"`
+	Processor any              `yaml:"processor"`
+	Transform *transformConfig `yaml:"transform,omitempty"`
+}
+
+type transformConfig struct {
+	NullPercentage int `yaml:"null_percentage,omitempty"`
+}
+
+// WriteConfig writes a dg config file for the given tables, ordering them
+// such that referenced tables are generated first. Tables without a Count
+// will be given the defaultCount.
+func WriteConfig(w io.Writer, tables []Table, defaultCount int) error {
+	c, err := buildConfig(tables, defaultCount)
+	if err != nil {
+		return fmt.Errorf("building config: %w", err)
+	}
+
+	return encodeConfig(w, c)
+}
+
+func encodeConfig(w io.Writer, c configFile) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(c); err != nil {
+		return fmt.Errorf("encoding config: %w", err)
+	}
+
+	return enc.Close()
+}
+
+func buildConfig(tables []Table, defaultCount int) (configFile, error) {
+	names := lo.Map(tables, func(t Table, _ int) string {
+		return t.Name
+	})
+
+	deps := make([][]string, len(tables))
+	for i, t := range tables {
+		for _, c := range t.Columns {
+			if c.Reference != nil {
+				deps[i] = append(deps[i], c.Reference.Table)
+			}
+		}
+	}
+
+	order, err := model.OrderByDependencies(names, deps)
+	if err != nil {
+		return configFile{}, fmt.Errorf("ordering tables: %w", err)
+	}
+
+	var c configFile
+	for _, i := range order {
+		t := tables[i]
+
+		tc := tableConfig{
+			Name:          t.Name,
+			Count:         lo.Ternary(t.Count > 0, t.Count, defaultCount),
+			UniqueColumns: uniqueColumns(t),
+		}
+
+		for _, col := range t.Columns {
+			if col.Computed {
+				continue
+			}
+
+			typ, processor := processorFor(t, col)
+			tc.Columns = append(tc.Columns, columnConfig{
+				Name:      col.Name,
+				Type:      typ,
+				Processor: processor,
+			})
+		}
+
+		c.Tables = append(c.Tables, tc)
+	}
+
+	return c, nil
+}
+
+// uniqueColumns returns a composite key that needs to be unique across the
+// table (e.g. the primary key of a many-to-many resolver table).
+func uniqueColumns(t Table) []string {
+	if len(t.PrimaryKey) > 1 {
+		return t.PrimaryKey
+	}
+
+	for _, u := range t.Unique {
+		if len(u) > 1 {
+			return u
+		}
+	}
+
+	return nil
+}
+
+// namedPlaceholders maps column name suffixes to the placeholder most
+// likely to generate sensible values for them.
+var namedPlaceholders = []string{
+	"email", "phone", "city", "country", "url", "username", "company", "zip", "state", "street", "name",
+}
+
+func processorFor(t Table, c Column) (string, any) {
+	// Self-references can't be generated as refs, as the table won't
+	// exist until all of its columns have been generated.
+	if c.Reference != nil && c.Reference.Table != t.Name {
+		return "ref", generator.RefGenerator{
+			Table:  c.Reference.Table,
+			Column: c.Reference.Column,
+		}
+	}
+
+	if len(c.Values) > 0 {
+		return "set", generator.SetGenerator{Values: c.Values}
+	}
+
+	switch c.Type {
+	case "serial", "serial2", "serial4", "serial8", "smallserial", "bigserial":
+		return "inc", generator.IncGenerator{Start: 1}
+
+	case "smallint", "int2", "tinyint", "mediumint", "int", "int4", "integer", "bigint", "int8", "int64":
+		if c.AutoIncrement || c.PrimaryKey {
+			return "inc", generator.IncGenerator{Start: 1}
+		}
+		return "gen", generator.GenGenerator{Value: intPlaceholder(c.Type)}
+
+	case "uuid":
+		return "gen", generator.GenGenerator{Value: "${uuid}"}
+
+	case "bool", "boolean":
+		return "gen", generator.GenGenerator{Value: "${bool}"}
+
+	case "decimal", "numeric", "dec", "money":
+		return "gen", generator.GenGenerator{Pattern: decimalPattern(c.Length, c.Scale)}
+
+	case "float", "float4", "float8", "real", "double":
+		return "gen", generator.GenGenerator{Pattern: decimalPattern(6, 2)}
+
+	case "date":
+		return "range", generator.RangeGenerator{Type: "date", From: dateFrom[:10], To: dateTo[:10], Format: dateFormat}
+
+	case "timestamp", "timestamptz", "datetime":
+		return "range", generator.RangeGenerator{Type: "date", From: dateFrom, To: dateTo, Format: timestampFormat}
+
+	case "time", "timetz":
+		return "gen", generator.GenGenerator{Value: "${date}", Format: "15:04:05"}
+
+	case "inet":
+		return "gen", generator.GenGenerator{Value: "${ipv4_address}"}
+
+	case "json", "jsonb":
+		return "gen", generator.GenGenerator{Value: "{}"}
+	}
+
+	// Anything else is treated as text.
+	if p, ok := namedPlaceholder(c.Name); ok {
+		return "gen", generator.GenGenerator{Value: p}
+	}
+
+	if c.Length > 0 {
+		return "gen", generator.GenGenerator{Pattern: fmt.Sprintf("[a-z]{1,%d}", lo.Min([]int{c.Length, maxTextLength}))}
+	}
+
+	return "gen", generator.GenGenerator{Value: "${word}"}
+}
+
+func namedPlaceholder(column string) (string, bool) {
+	column = strings.ToLower(column)
+	placeholders := generator.Placeholders()
+
+	if p := fmt.Sprintf("${%s}", column); lo.Contains(placeholders, p) {
+		return p, true
+	}
+
+	for _, name := range namedPlaceholders {
+		if strings.HasSuffix(column, "_"+name) {
+			return fmt.Sprintf("${%s}", name), true
+		}
+	}
+
+	return "", false
+}
+
+func intPlaceholder(typ string) string {
+	switch typ {
+	case "smallint", "int2", "tinyint":
+		return "${uint8}"
+	case "bigint", "int8", "int64":
+		return "${uint32}"
+	default:
+		return "${uint16}"
+	}
+}
+
+func decimalPattern(precision, scale int) string {
+	if precision == 0 {
+		precision = 10
+	}
+
+	// Avoid leading zeros in the whole part of the number.
+	whole := `\d`
+	if digits := precision - scale; digits > 1 {
+		whole = fmt.Sprintf(`[1-9]\d{0,%d}`, digits-1)
+	}
+
+	if scale == 0 {
+		return whole
+	}
+
+	return fmt.Sprintf(`%s\.\d{%d}`, whole, scale)
+}
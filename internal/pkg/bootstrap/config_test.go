@@ -0,0 +1,144 @@
+package bootstrap
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/codingconcepts/dg/internal/pkg/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteConfig(t *testing.T) {
+	tables := []Table{
+		{
+			Name: "person_event",
+			Columns: []Column{
+				{Name: "person_id", Type: "uuid", Reference: &Reference{Table: "person", Column: "id"}},
+				{Name: "event_id", Type: "uuid", Reference: &Reference{Table: "event", Column: "id"}},
+			},
+			PrimaryKey: []string{"person_id", "event_id"},
+		},
+		{
+			Name:  "person",
+			Count: 10,
+			Columns: []Column{
+				{Name: "id", Type: "uuid", PrimaryKey: true},
+				{Name: "status", Type: "text", Values: []string{"active", "inactive"}},
+			},
+		},
+		{
+			Name: "event",
+			Columns: []Column{
+				{Name: "id", Type: "int", PrimaryKey: true},
+				{Name: "total", Type: "int", Computed: true},
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, WriteConfig(buf, tables, 100))
+
+	exp := `tables:
+  - name: person
+    count: 10
+    columns:
+      - name: id
+        type: gen
+        processor:
+          value: ${uuid}
+      - name: status
+        type: set
+        processor:
+          values: [active, inactive]
+  - name: event
+    count: 100
+    columns:
+      - name: id
+        type: inc
+        processor:
+          start: 1
+  - name: person_event
+    count: 100
+    unique_columns: [person_id, event_id]
+    columns:
+      - name: person_id
+        type: ref
+        processor:
+          table: person
+          column: id
+      - name: event_id
+        type: ref
+        processor:
+          table: event
+          column: id
+`
+
+	assert.Equal(t, exp, buf.String())
+}
+
+func TestProcessorFor(t *testing.T) {
+	cases := []struct {
+		name         string
+		column       Column
+		expType      string
+		expProcessor any
+	}{
+		{
+			name:         "self reference",
+			column:       Column{Name: "manager_id", Type: "uuid", Reference: &Reference{Table: "employee", Column: "id"}},
+			expType:      "gen",
+			expProcessor: generator.GenGenerator{Value: "${uuid}"},
+		},
+		{
+			name:         "auto increment",
+			column:       Column{Name: "id", Type: "bigint", AutoIncrement: true},
+			expType:      "inc",
+			expProcessor: generator.IncGenerator{Start: 1},
+		},
+		{
+			name:         "int",
+			column:       Column{Name: "age", Type: "smallint"},
+			expType:      "gen",
+			expProcessor: generator.GenGenerator{Value: "${uint8}"},
+		},
+		{
+			name:         "decimal",
+			column:       Column{Name: "price", Type: "decimal", Length: 10, Scale: 2},
+			expType:      "gen",
+			expProcessor: generator.GenGenerator{Pattern: `[1-9]\d{0,7}\.\d{2}`},
+		},
+		{
+			name:         "timestamp",
+			column:       Column{Name: "created_at", Type: "timestamptz"},
+			expType:      "range",
+			expProcessor: generator.RangeGenerator{Type: "date", From: dateFrom, To: dateTo, Format: timestampFormat},
+		},
+		{
+			name:         "named text",
+			column:       Column{Name: "contact_email", Type: "varchar", Length: 255},
+			expType:      "gen",
+			expProcessor: generator.GenGenerator{Value: "${email}"},
+		},
+		{
+			name:         "bounded text",
+			column:       Column{Name: "code", Type: "varchar", Length: 3},
+			expType:      "gen",
+			expProcessor: generator.GenGenerator{Pattern: "[a-z]{1,3}"},
+		},
+		{
+			name:         "unbounded text",
+			column:       Column{Name: "notes", Type: "text"},
+			expType:      "gen",
+			expProcessor: generator.GenGenerator{Value: "${word}"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actType, actProcessor := processorFor(Table{Name: "employee"}, c.column)
+
+			assert.Equal(t, c.expType, actType)
+			assert.Equal(t, c.expProcessor, actProcessor)
+		})
+	}
+}
@@ -0,0 +1,811 @@
+package bootstrap
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/samber/lo"
+)
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenSymbol
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+// ParseDDL reads the CREATE TABLE, CREATE TYPE, and ALTER TABLE statements
+// from a Postgres, CockroachDB, or MySQL DDL script and returns the tables
+// they describe. Other statements are ignored.
+func ParseDDL(r io.Reader) ([]Table, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading ddl: %w", err)
+	}
+
+	tokens, err := tokenize(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("tokenizing ddl: %w", err)
+	}
+
+	p := &ddlParser{
+		parser: parser{tokens: tokens},
+		enums:  map[string][]string{},
+	}
+	if err = p.parse(); err != nil {
+		return nil, err
+	}
+
+	return p.resolve(), nil
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	runes := []rune(s)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-', r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			end := i + 2
+			for end+1 < len(runes) && !(runes[end] == '*' && runes[end+1] == '/') {
+				end++
+			}
+			if end+1 >= len(runes) {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i = end + 2
+
+		case r == '\'', r == '"', r == '`':
+			value, n, err := quoted(runes[i:], r)
+			if err != nil {
+				return nil, err
+			}
+
+			kind := tokenIdent
+			if r == '\'' {
+				kind = tokenString
+			}
+			tokens = append(tokens, token{kind: kind, value: value})
+			i += n
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '$') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, value: string(runes[start:i])})
+
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: string(runes[start:i])})
+
+		default:
+			tokens = append(tokens, token{kind: tokenSymbol, value: string(r)})
+			i++
+		}
+	}
+
+	return tokens, nil
+}
+
+// quoted reads a quoted value, where a doubled quote character represents
+// an escaped quote, returning the unquoted value and the number of runes read.
+func quoted(runes []rune, quote rune) (string, int, error) {
+	var sb strings.Builder
+
+	for i := 1; i < len(runes); i++ {
+		if runes[i] != quote {
+			sb.WriteRune(runes[i])
+			continue
+		}
+
+		if i+1 < len(runes) && runes[i+1] == quote {
+			sb.WriteRune(quote)
+			i++
+			continue
+		}
+
+		return sb.String(), i + 1, nil
+	}
+
+	return "", 0, fmt.Errorf("unterminated quoted value")
+}
+
+// parser provides helpers for walking a collection of tokens.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *parser) isWord(words ...string) bool {
+	t := p.peek()
+	return t.kind == tokenWord && lo.Contains(words, strings.ToUpper(t.value))
+}
+
+// acceptWords consumes the given sequence of words, returning true if they
+// were all found, or leaving the parser untouched if they weren't.
+func (p *parser) acceptWords(words ...string) bool {
+	for i, w := range words {
+		if p.pos+i >= len(p.tokens) {
+			return false
+		}
+
+		t := p.tokens[p.pos+i]
+		if t.kind != tokenWord || !strings.EqualFold(t.value, w) {
+			return false
+		}
+	}
+
+	p.pos += len(words)
+	return true
+}
+
+func (p *parser) isSymbol(s string) bool {
+	t := p.peek()
+	return t.kind == tokenSymbol && t.value == s
+}
+
+func (p *parser) acceptSymbol(s string) bool {
+	if p.isSymbol(s) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// name reads a possibly schema-qualified name and returns its last part.
+func (p *parser) name() (string, error) {
+	t := p.next()
+	if t.kind != tokenWord && t.kind != tokenIdent {
+		return "", fmt.Errorf("expected name but found %q", t.value)
+	}
+
+	for p.acceptSymbol(".") {
+		if t = p.next(); t.kind != tokenWord && t.kind != tokenIdent {
+			return "", fmt.Errorf("expected name but found %q", t.value)
+		}
+	}
+
+	return t.value, nil
+}
+
+// group reads a parenthesised group and returns the tokens within it.
+func (p *parser) group() ([]token, error) {
+	if !p.acceptSymbol("(") {
+		return nil, fmt.Errorf("expected '(' but found %q", p.peek().value)
+	}
+
+	start := p.pos
+	for depth := 1; !p.done(); {
+		t := p.next()
+		if t.kind != tokenSymbol {
+			continue
+		}
+
+		switch t.value {
+		case "(":
+			depth++
+		case ")":
+			if depth--; depth == 0 {
+				return p.tokens[start : p.pos-1], nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("unterminated group")
+}
+
+// skipGroup skips over a parenthesised group, if there is one.
+func (p *parser) skipGroup() {
+	if p.isSymbol("(") {
+		p.group()
+	}
+}
+
+// names reads a parenthesised list of names.
+func (p *parser) names() ([]string, error) {
+	tokens, err := p.group()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, part := range split(tokens) {
+		if len(part) > 0 {
+			names = append(names, part[0].value)
+		}
+	}
+
+	return names, nil
+}
+
+// skipStatement moves the parser past the next top-level semicolon.
+func (p *parser) skipStatement() {
+	for !p.done() {
+		if p.isSymbol("(") {
+			p.group()
+			continue
+		}
+
+		if t := p.next(); t.kind == tokenSymbol && t.value == ";" {
+			return
+		}
+	}
+}
+
+// statement reads the tokens up to the next top-level semicolon.
+func (p *parser) statement() []token {
+	start := p.pos
+	p.skipStatement()
+
+	end := p.pos
+	if end > start && p.tokens[end-1].kind == tokenSymbol && p.tokens[end-1].value == ";" {
+		end--
+	}
+
+	return p.tokens[start:end]
+}
+
+// split divides tokens by their top-level commas.
+func split(tokens []token) [][]token {
+	var parts [][]token
+
+	depth, start := 0, 0
+	for i, t := range tokens {
+		if t.kind != tokenSymbol {
+			continue
+		}
+
+		switch t.value {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case ",":
+			if depth == 0 {
+				parts = append(parts, tokens[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(parts, tokens[start:])
+}
+
+type ddlParser struct {
+	parser
+
+	tables []*Table
+	enums  map[string][]string
+}
+
+func (p *ddlParser) parse() error {
+	for !p.done() {
+		switch {
+		case p.acceptWords("CREATE"):
+			p.acceptWords("OR", "REPLACE")
+			for p.isWord("TEMP", "TEMPORARY", "UNLOGGED", "GLOBAL", "LOCAL") {
+				p.next()
+			}
+
+			switch {
+			case p.acceptWords("TABLE"):
+				if err := p.parseCreateTable(); err != nil {
+					return fmt.Errorf("parsing create table: %w", err)
+				}
+			case p.acceptWords("TYPE"):
+				if err := p.parseCreateType(); err != nil {
+					return fmt.Errorf("parsing create type: %w", err)
+				}
+			default:
+				p.skipStatement()
+			}
+
+		case p.acceptWords("ALTER", "TABLE"):
+			if err := p.parseAlterTable(); err != nil {
+				return fmt.Errorf("parsing alter table: %w", err)
+			}
+
+		default:
+			p.skipStatement()
+		}
+	}
+
+	return nil
+}
+
+func (p *ddlParser) parseCreateTable() error {
+	p.acceptWords("IF", "NOT", "EXISTS")
+
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+
+	// Ignore tables created from queries (e.g. CREATE TABLE ... AS SELECT).
+	if !p.isSymbol("(") {
+		p.skipStatement()
+		return nil
+	}
+
+	body, err := p.group()
+	if err != nil {
+		return fmt.Errorf("parsing table %q: %w", name, err)
+	}
+
+	t := &Table{Name: name}
+	for _, element := range split(body) {
+		if err = parseElement(t, element); err != nil {
+			return fmt.Errorf("parsing table %q: %w", name, err)
+		}
+	}
+
+	p.tables = append(p.tables, t)
+	p.skipStatement()
+	return nil
+}
+
+func (p *ddlParser) parseCreateType() error {
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+
+	if !p.acceptWords("AS", "ENUM") {
+		p.skipStatement()
+		return nil
+	}
+
+	values, err := p.group()
+	if err != nil {
+		return fmt.Errorf("parsing enum %q: %w", name, err)
+	}
+
+	p.enums[strings.ToLower(name)] = stringValues(values)
+	p.skipStatement()
+	return nil
+}
+
+func (p *ddlParser) parseAlterTable() error {
+	p.acceptWords("IF", "EXISTS")
+	p.acceptWords("ONLY")
+
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+
+	t, ok := lo.Find(p.tables, func(t *Table) bool {
+		return t.Name == name
+	})
+	if !ok {
+		p.skipStatement()
+		return nil
+	}
+
+	for _, action := range split(p.statement()) {
+		ap := parser{tokens: action}
+		if !ap.acceptWords("ADD") {
+			continue
+		}
+
+		// Only constraints are of interest, so skip any added columns.
+		if ap.isWord("CONSTRAINT", "PRIMARY", "UNIQUE", "FOREIGN", "CHECK") {
+			if err = parseElement(t, ap.tokens[ap.pos:]); err != nil {
+				return fmt.Errorf("parsing table %q: %w", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseElement parses a column definition or table constraint.
+func parseElement(t *Table, tokens []token) error {
+	p := &parser{tokens: tokens}
+	if p.done() {
+		return nil
+	}
+
+	if p.acceptWords("CONSTRAINT") {
+		p.next()
+	}
+
+	switch {
+	case p.acceptWords("PRIMARY", "KEY"):
+		cols, err := p.names()
+		if err != nil {
+			return fmt.Errorf("parsing primary key: %w", err)
+		}
+
+		t.PrimaryKey = cols
+		if len(cols) == 1 {
+			if c := t.column(cols[0]); c != nil {
+				c.PrimaryKey = true
+			}
+		}
+		return nil
+
+	case p.acceptWords("UNIQUE"):
+		// Skip the optional KEY/INDEX keyword and index name.
+		for !p.done() && !p.isSymbol("(") {
+			p.next()
+		}
+
+		cols, err := p.names()
+		if err != nil {
+			return fmt.Errorf("parsing unique constraint: %w", err)
+		}
+
+		t.Unique = append(t.Unique, cols)
+		if len(cols) == 1 {
+			if c := t.column(cols[0]); c != nil {
+				c.Unique = true
+			}
+		}
+		return nil
+
+	case p.acceptWords("FOREIGN", "KEY"):
+		for !p.done() && !p.isSymbol("(") {
+			p.next()
+		}
+
+		cols, err := p.names()
+		if err != nil {
+			return fmt.Errorf("parsing foreign key: %w", err)
+		}
+
+		if !p.acceptWords("REFERENCES") {
+			return fmt.Errorf("expected REFERENCES in foreign key")
+		}
+
+		ref, err := p.name()
+		if err != nil {
+			return fmt.Errorf("parsing foreign key: %w", err)
+		}
+
+		var refCols []string
+		if p.isSymbol("(") {
+			if refCols, err = p.names(); err != nil {
+				return fmt.Errorf("parsing foreign key: %w", err)
+			}
+		}
+
+		for i, col := range cols {
+			c := t.column(col)
+			if c == nil {
+				continue
+			}
+
+			c.Reference = &Reference{Table: ref}
+			if i < len(refCols) {
+				c.Reference.Column = refCols[i]
+			}
+		}
+		return nil
+
+	case p.acceptWords("CHECK"):
+		check, err := p.group()
+		if err != nil {
+			return fmt.Errorf("parsing check constraint: %w", err)
+		}
+
+		if col, values, ok := inValues(check); ok {
+			if c := t.column(col); c != nil {
+				c.Values = values
+			}
+		}
+		return nil
+
+	case isIndex(p):
+		return nil
+	}
+
+	c, err := parseColumn(p)
+	if err != nil {
+		return err
+	}
+
+	if c.PrimaryKey {
+		t.PrimaryKey = []string{c.Name}
+	}
+	if c.Unique {
+		t.Unique = append(t.Unique, []string{c.Name})
+	}
+
+	t.Columns = append(t.Columns, c)
+	return nil
+}
+
+// isIndex returns true if the parser is positioned at an index or column
+// family definition, rather than a column that happens to be called "key".
+func isIndex(p *parser) bool {
+	switch {
+	case p.isWord("FULLTEXT", "SPATIAL", "FAMILY", "EXCLUDE", "LIKE"):
+		return true
+	case p.isWord("KEY", "INDEX", "INVERTED"):
+		rest := p.tokens[p.pos+1:]
+		if len(rest) > 0 && rest[0].kind == tokenSymbol && rest[0].value == "(" {
+			return true
+		}
+		// Distinguish between "INDEX idx (a)" and "key VARCHAR(10)".
+		return len(rest) > 2 && rest[1].value == "(" && rest[2].kind != tokenNumber
+	}
+
+	return false
+}
+
+var constraintWords = []string{
+	"NOT", "NULL", "PRIMARY", "UNIQUE", "REFERENCES", "CHECK", "CONSTRAINT", "DEFAULT",
+	"AUTO_INCREMENT", "AUTOINCREMENT", "GENERATED", "AS", "COLLATE", "COMMENT", "ON",
+}
+
+func parseColumn(p *parser) (Column, error) {
+	name, err := p.name()
+	if err != nil {
+		return Column{}, fmt.Errorf("parsing column: %w", err)
+	}
+
+	c := Column{Name: name, Nullable: true}
+	if err = parseType(p, &c); err != nil {
+		return Column{}, fmt.Errorf("parsing type for column %q: %w", name, err)
+	}
+
+	for !p.done() {
+		switch {
+		case p.acceptWords("NOT", "NULL"):
+			c.Nullable = false
+
+		case p.acceptWords("NULL"):
+			c.Nullable = true
+
+		case p.acceptWords("PRIMARY", "KEY"):
+			c.PrimaryKey = true
+			c.Nullable = false
+
+		case p.acceptWords("UNIQUE"):
+			p.acceptWords("KEY")
+			c.Unique = true
+
+		case p.acceptWords("REFERENCES"):
+			ref, err := p.name()
+			if err != nil {
+				return Column{}, fmt.Errorf("parsing reference for column %q: %w", name, err)
+			}
+
+			c.Reference = &Reference{Table: ref}
+			if p.isSymbol("(") {
+				cols, err := p.names()
+				if err != nil {
+					return Column{}, fmt.Errorf("parsing reference for column %q: %w", name, err)
+				}
+				if len(cols) > 0 {
+					c.Reference.Column = cols[0]
+				}
+			}
+
+		case p.acceptWords("CHECK"):
+			check, err := p.group()
+			if err != nil {
+				return Column{}, fmt.Errorf("parsing check for column %q: %w", name, err)
+			}
+
+			if _, values, ok := inValues(check); ok {
+				c.Values = values
+			}
+
+		case p.acceptWords("DEFAULT"):
+			for !p.done() && !p.isWord(constraintWords...) {
+				if t := p.next(); t.kind == tokenWord {
+					switch strings.ToLower(t.value) {
+					case "nextval", "unique_rowid":
+						c.AutoIncrement = true
+					}
+				}
+				p.skipGroup()
+			}
+
+		case p.acceptWords("AUTO_INCREMENT"), p.acceptWords("AUTOINCREMENT"):
+			c.AutoIncrement = true
+
+		case p.acceptWords("GENERATED"):
+			for !p.done() && !p.isWord("AS") {
+				p.next()
+			}
+			p.acceptWords("AS")
+
+			if p.acceptWords("IDENTITY") {
+				c.AutoIncrement = true
+				p.skipGroup()
+			} else {
+				c.Computed = true
+			}
+
+		case p.acceptWords("AS"):
+			c.Computed = true
+			p.skipGroup()
+
+		default:
+			p.next()
+			p.skipGroup()
+		}
+	}
+
+	return c, nil
+}
+
+// multiWordTypes maps the first word of a type name to the words that may
+// follow it.
+var multiWordTypes = map[string][]string{
+	"double":    {"PRECISION"},
+	"character": {"VARYING"},
+	"char":      {"VARYING"},
+	"bit":       {"VARYING"},
+	"timestamp": {"WITH", "WITHOUT", "TIME", "ZONE"},
+	"time":      {"WITH", "WITHOUT", "TIME", "ZONE"},
+}
+
+var typeAliases = map[string]string{
+	"character varying":           "varchar",
+	"char varying":                "varchar",
+	"character":                   "char",
+	"double precision":            "double",
+	"timestamp with time zone":    "timestamptz",
+	"timestamp without time zone": "timestamp",
+	"time with time zone":         "timetz",
+	"time without time zone":      "time",
+}
+
+func parseType(p *parser, c *Column) error {
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+
+	parts := []string{strings.ToLower(name)}
+	if follow, ok := multiWordTypes[parts[0]]; ok {
+		for p.isWord(follow...) {
+			parts = append(parts, strings.ToLower(p.next().value))
+		}
+	}
+
+	c.Type = strings.Join(parts, " ")
+	if alias, ok := typeAliases[c.Type]; ok {
+		c.Type = alias
+	}
+
+	if p.isSymbol("(") {
+		args, err := p.group()
+		if err != nil {
+			return err
+		}
+
+		var numbers []int
+		for _, arg := range args {
+			switch arg.kind {
+			case tokenNumber:
+				n, _ := strconv.Atoi(arg.value)
+				numbers = append(numbers, n)
+			case tokenString:
+				c.Values = append(c.Values, arg.value)
+			}
+		}
+
+		if len(numbers) > 0 {
+			c.Length = numbers[0]
+		}
+		if len(numbers) > 1 {
+			c.Scale = numbers[1]
+		}
+	}
+
+	for p.isWord("UNSIGNED", "SIGNED", "ZEROFILL", "ARRAY") || p.isSymbol("[") || p.isSymbol("]") {
+		p.next()
+	}
+
+	return nil
+}
+
+// inValues looks for a "column IN (values...)" expression in a CHECK
+// constraint and returns the column and values.
+func inValues(tokens []token) (string, []string, bool) {
+	for i := 1; i < len(tokens)-1; i++ {
+		t := tokens[i]
+		if t.kind != tokenWord || !strings.EqualFold(t.value, "IN") {
+			continue
+		}
+
+		p := &parser{tokens: tokens[i+1:]}
+		values, err := p.group()
+		if err != nil {
+			return "", nil, false
+		}
+
+		return tokens[i-1].value, stringValues(values), true
+	}
+
+	return "", nil, false
+}
+
+func stringValues(tokens []token) []string {
+	var values []string
+	for _, t := range tokens {
+		if t.kind == tokenString || t.kind == tokenNumber {
+			values = append(values, t.value)
+		}
+	}
+
+	return values
+}
+
+// resolve applies enum types and fills in referenced columns that weren't
+// explicitly named, which default to the referenced table's primary key.
+func (p *ddlParser) resolve() []Table {
+	tables := make([]Table, len(p.tables))
+
+	for i, t := range p.tables {
+		for j := range t.Columns {
+			c := &t.Columns[j]
+
+			if values, ok := p.enums[c.Type]; ok && len(c.Values) == 0 {
+				c.Values = values
+			}
+
+			if c.Reference == nil || c.Reference.Column != "" {
+				continue
+			}
+
+			c.Reference.Column = "id"
+			if ref, ok := lo.Find(p.tables, func(rt *Table) bool {
+				return rt.Name == c.Reference.Table
+			}); ok && len(ref.PrimaryKey) == 1 {
+				c.Reference.Column = ref.PrimaryKey[0]
+			}
+		}
+
+		tables[i] = *t
+	}
+
+	return tables
+}
@@ -0,0 +1,139 @@
+package bootstrap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDDL(t *testing.T) {
+	cases := []struct {
+		name   string
+		ddl    string
+		exp    []Table
+		expErr string
+	}{
+		{
+			name: "postgres column constraints",
+			ddl: `
+CREATE TABLE "person" (
+  "id" UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  "email" VARCHAR(255) NOT NULL UNIQUE,
+  "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);`,
+			exp: []Table{
+				{
+					Name: "person",
+					Columns: []Column{
+						{Name: "id", Type: "uuid", PrimaryKey: true},
+						{Name: "email", Type: "varchar", Length: 255, Unique: true},
+						{Name: "created_at", Type: "timestamptz"},
+					},
+					PrimaryKey: []string{"id"},
+					Unique:     [][]string{{"email"}},
+				},
+			},
+		},
+		{
+			name: "table constraints",
+			ddl: `
+CREATE TABLE IF NOT EXISTS public.person_event (
+  person_id UUID NOT NULL,
+  event_id UUID NOT NULL REFERENCES event,
+  status STRING NOT NULL,
+  price DECIMAL(10, 2),
+  PRIMARY KEY (person_id, event_id),
+  CONSTRAINT fk_person FOREIGN KEY (person_id) REFERENCES person (id),
+  CONSTRAINT chk_status CHECK (status IN ('going', 'not going'))
+);`,
+			exp: []Table{
+				{
+					Name: "person_event",
+					Columns: []Column{
+						{Name: "person_id", Type: "uuid", Reference: &Reference{Table: "person", Column: "id"}},
+						{Name: "event_id", Type: "uuid", Reference: &Reference{Table: "event", Column: "id"}},
+						{Name: "status", Type: "string", Values: []string{"going", "not going"}},
+						{Name: "price", Type: "decimal", Length: 10, Scale: 2, Nullable: true},
+					},
+					PrimaryKey: []string{"person_id", "event_id"},
+				},
+			},
+		},
+		{
+			name: "mysql",
+			ddl: "CREATE TABLE `product` (\n" +
+				"  `id` INT UNSIGNED NOT NULL AUTO_INCREMENT,\n" +
+				"  `size` ENUM('s', 'm', 'l') DEFAULT 's',\n" +
+				"  `key` VARCHAR(10),\n" +
+				"  PRIMARY KEY (`id`),\n" +
+				"  KEY `idx_size` (`size`)\n" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;",
+			exp: []Table{
+				{
+					Name: "product",
+					Columns: []Column{
+						{Name: "id", Type: "int", AutoIncrement: true, PrimaryKey: true},
+						{Name: "size", Type: "enum", Values: []string{"s", "m", "l"}, Nullable: true},
+						{Name: "key", Type: "varchar", Length: 10, Nullable: true},
+					},
+					PrimaryKey: []string{"id"},
+				},
+			},
+		},
+		{
+			name: "enum types, alter table, and ignored statements",
+			ddl: `
+-- Types
+CREATE TYPE mood AS ENUM ('happy', 'sad');
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+/* Tables */
+CREATE TABLE parent (id SERIAL PRIMARY KEY);
+CREATE TABLE child (
+  id BIGINT GENERATED ALWAYS AS IDENTITY,
+  parent_id INT NOT NULL,
+  mood mood,
+  total INT GENERATED ALWAYS AS (1 + 1) STORED
+);
+CREATE INDEX ON child (parent_id);
+ALTER TABLE ONLY child ADD CONSTRAINT fk_parent FOREIGN KEY (parent_id) REFERENCES parent;`,
+			exp: []Table{
+				{
+					Name: "parent",
+					Columns: []Column{
+						{Name: "id", Type: "serial", PrimaryKey: true},
+					},
+					PrimaryKey: []string{"id"},
+				},
+				{
+					Name: "child",
+					Columns: []Column{
+						{Name: "id", Type: "bigint", AutoIncrement: true, Nullable: true},
+						{Name: "parent_id", Type: "int", Reference: &Reference{Table: "parent", Column: "id"}},
+						{Name: "mood", Type: "mood", Values: []string{"happy", "sad"}, Nullable: true},
+						{Name: "total", Type: "int", Computed: true, Nullable: true},
+					},
+				},
+			},
+		},
+		{
+			name:   "unterminated string",
+			ddl:    `CREATE TABLE a (b TEXT DEFAULT 'abc);`,
+			expErr: "tokenizing ddl: unterminated quoted value",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			act, err := ParseDDL(strings.NewReader(c.ddl))
+			if c.expErr != "" {
+				assert.EqualError(t, err, c.expErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, c.exp, act)
+		})
+	}
+}
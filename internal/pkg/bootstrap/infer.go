@@ -0,0 +1,478 @@
+package bootstrap
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/codingconcepts/dg/internal/pkg/generator"
+	"github.com/samber/lo"
+)
+
+// Sample is a CSV extract whose data a generated table should resemble.
+type Sample struct {
+	Name string
+	Data io.Reader
+}
+
+var (
+	uuidRegex   = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	emailRegex  = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	numberRegex = regexp.MustCompile(`^-?(0|[1-9]\d*)(\.\d+)?$`)
+
+	dateLayouts = []string{
+		"2006-01-02",
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02 15:04:05Z07:00",
+	}
+)
+
+// InferConfig profiles the columns of each CSV sample and writes a config
+// containing a table for each sample that will generate similar data. Columns
+// with maxSetSize or fewer distinct values are generated from a weighted set.
+func InferConfig(w io.Writer, samples []Sample, maxSetSize int) error {
+	var c configFile
+
+	for _, s := range samples {
+		t, err := inferTable(s, maxSetSize)
+		if err != nil {
+			return fmt.Errorf("inferring table %q: %w", s.Name, err)
+		}
+		c.Tables = append(c.Tables, t)
+	}
+
+	return encodeConfig(w, c)
+}
+
+func inferTable(s Sample, maxSetSize int) (tableConfig, error) {
+	rows, err := csv.NewReader(s.Data).ReadAll()
+	if err != nil {
+		return tableConfig{}, fmt.Errorf("reading csv: %w", err)
+	}
+
+	if len(rows) == 0 {
+		return tableConfig{}, fmt.Errorf("missing csv header")
+	}
+
+	t := tableConfig{
+		Name:  s.Name,
+		Count: len(rows) - 1,
+	}
+
+	columns := generator.Transpose(rows[1:])
+	for i, name := range rows[0] {
+		var values []string
+		if i < len(columns) {
+			values = columns[i]
+		}
+
+		p := profileColumn(name, values)
+		typ, processor := p.processor(maxSetSize)
+		t.Columns = append(t.Columns, columnConfig{
+			Name:      name,
+			Type:      typ,
+			Processor: processor,
+			Transform: p.transform(typ),
+		})
+	}
+
+	return t, nil
+}
+
+// columnProfile describes the values found in a sample column.
+type columnProfile struct {
+	name        string
+	rows        int
+	values      []string
+	frequencies map[string]int
+	distinct    []string
+}
+
+func profileColumn(name string, values []string) columnProfile {
+	p := columnProfile{
+		name:        name,
+		rows:        len(values),
+		frequencies: map[string]int{},
+	}
+
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+
+		if _, ok := p.frequencies[v]; !ok {
+			p.distinct = append(p.distinct, v)
+		}
+		p.frequencies[v]++
+		p.values = append(p.values, v)
+	}
+
+	return p
+}
+
+func (p columnProfile) nullPercentage() int {
+	if p.rows == 0 {
+		return 0
+	}
+
+	return int(math.Round(float64(p.rows-len(p.values)) * 100 / float64(p.rows)))
+}
+
+func (p columnProfile) unique() bool {
+	return len(p.distinct) == len(p.values)
+}
+
+func (p columnProfile) processor(maxSetSize int) (string, any) {
+	nullPercentage := p.nullPercentage()
+
+	if len(p.values) == 0 {
+		return "const", generator.ConstGenerator{Values: []string{""}}
+	}
+
+	if len(p.distinct) <= maxSetSize && !p.unique() {
+		return "set", p.set()
+	}
+
+	if from, step, ok := p.sequence(); ok && nullPercentage == 0 {
+		return "range", generator.RangeGenerator{
+			Type: "int",
+			From: strconv.Itoa(from),
+			To:   strconv.Itoa(from + step*(len(p.values)-1)),
+			Step: strconv.Itoa(step),
+		}
+	}
+
+	if from, to, layout, ok := p.dateRange(); ok && nullPercentage == 0 {
+		return "range", generator.RangeGenerator{
+			Type:   "date",
+			From:   from,
+			To:     to,
+			Format: layout,
+		}
+	}
+
+	if min, max, precision, ok := p.numberRange(); ok {
+		return "dist", generator.DistGenerator{
+			Type:      "uniform",
+			Min:       &min,
+			Max:       &max,
+			Precision: &precision,
+		}
+	}
+
+	gen := generator.GenGenerator{NullPercentage: nullPercentage}
+
+	switch {
+	case p.all(uuidRegex.MatchString):
+		gen.Value = "${uuid}"
+	case p.all(emailRegex.MatchString):
+		gen.Value = "${email}"
+	default:
+		if placeholder, ok := namedPlaceholder(p.name); ok && !p.all(isNumeric) {
+			gen.Value = placeholder
+		} else {
+			gen.Pattern = p.pattern()
+		}
+	}
+
+	return "gen", gen
+}
+
+// transform returns the transform for a column generated by a processor of
+// the given type, which gives the column its nulls if the processor can't.
+func (p columnProfile) transform(typ string) *transformConfig {
+	if typ != "dist" || p.nullPercentage() == 0 {
+		return nil
+	}
+
+	return &transformConfig{NullPercentage: p.nullPercentage()}
+}
+
+// set returns the column's values, most frequent first, weighted by their
+// frequency. Null values are represented by an empty value.
+func (p columnProfile) set() generator.SetGenerator {
+	values := append([]string{}, p.distinct...)
+	sort.SliceStable(values, func(i, j int) bool {
+		return p.frequencies[values[i]] > p.frequencies[values[j]]
+	})
+
+	weights := lo.Map(values, func(v string, _ int) int {
+		return p.frequencies[v]
+	})
+
+	if nulls := p.rows - len(p.values); nulls > 0 {
+		values = append(values, "")
+		weights = append(weights, nulls)
+	}
+
+	return generator.SetGenerator{Values: values, Weights: weights}
+}
+
+// sequence returns the start and step of a column whose integer values
+// increase by the same amount on every row.
+func (p columnProfile) sequence() (int, int, bool) {
+	if len(p.values) < 2 {
+		return 0, 0, false
+	}
+
+	ints := make([]int, len(p.values))
+	for i, v := range p.values {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, 0, false
+		}
+		ints[i] = n
+	}
+
+	step := ints[1] - ints[0]
+	if step <= 0 {
+		return 0, 0, false
+	}
+
+	for i := 2; i < len(ints); i++ {
+		if ints[i]-ints[i-1] != step {
+			return 0, 0, false
+		}
+	}
+
+	return ints[0], step, true
+}
+
+// numberRange returns the smallest and largest values of a column whose
+// values are all numbers, and the largest number of decimal places found.
+// Numbers with leading zeros (e.g. "007") are codes rather than numbers.
+func (p columnProfile) numberRange() (float64, float64, int, bool) {
+	if !p.all(numberRegex.MatchString) {
+		return 0, 0, 0, false
+	}
+
+	var min, max float64
+	var precision int
+	for i, v := range p.values {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, 0, 0, false
+		}
+
+		if i == 0 || f < min {
+			min = f
+		}
+		if i == 0 || f > max {
+			max = f
+		}
+
+		if j := strings.IndexByte(v, '.'); j != -1 {
+			precision = lo.Max([]int{precision, len(v) - j - 1})
+		}
+	}
+
+	return min, max, precision, true
+}
+
+// dateRange returns the earliest and latest values of a column whose values
+// are all dates in the same layout.
+func (p columnProfile) dateRange() (string, string, string, bool) {
+	for _, layout := range dateLayouts {
+		var min, max time.Time
+		ok := p.all(func(v string) bool {
+			t, err := time.Parse(layout, v)
+			if err != nil {
+				return false
+			}
+
+			if min.IsZero() || t.Before(min) {
+				min = t
+			}
+			if max.IsZero() || t.After(max) {
+				max = t
+			}
+			return true
+		})
+
+		if ok {
+			return min.Format(layout), max.Format(layout), layout, true
+		}
+	}
+
+	return "", "", "", false
+}
+
+func (p columnProfile) all(f func(string) bool) bool {
+	return lo.EveryBy(p.values, f)
+}
+
+func isNumeric(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+// pattern returns a regular expression that describes the shape of the
+// column's values. If every value has the same shape (e.g. "AB-1234" and
+// "XY-98"), the pattern will mirror it (e.g. "[A-Z]{2}-\d{2,4}"), otherwise
+// it'll describe the characters used and the range of value lengths.
+func (p columnProfile) pattern() string {
+	for _, mergeLetters := range []bool{false, true} {
+		if pattern, ok := commonShape(p.values, mergeLetters); ok {
+			return pattern
+		}
+	}
+
+	return characterPattern(p.values)
+}
+
+type run struct {
+	class    string
+	min, max int
+}
+
+func commonShape(values []string, mergeLetters bool) (string, bool) {
+	var shape []run
+
+	for i, v := range values {
+		runs := shapeOf(v, mergeLetters)
+
+		if i == 0 {
+			shape = runs
+			continue
+		}
+
+		if len(runs) != len(shape) {
+			return "", false
+		}
+
+		for j := range runs {
+			if runs[j].class != shape[j].class {
+				return "", false
+			}
+			shape[j].min = lo.Min([]int{shape[j].min, runs[j].min})
+			shape[j].max = lo.Max([]int{shape[j].max, runs[j].max})
+		}
+	}
+
+	var sb strings.Builder
+	for _, r := range shape {
+		sb.WriteString(r.class)
+		sb.WriteString(quantifier(r.min, r.max))
+	}
+
+	return sb.String(), true
+}
+
+func shapeOf(s string, mergeLetters bool) []run {
+	var runs []run
+
+	for _, r := range s {
+		class := classOf(r, mergeLetters)
+
+		if len(runs) > 0 && runs[len(runs)-1].class == class {
+			runs[len(runs)-1].min++
+			runs[len(runs)-1].max++
+			continue
+		}
+
+		runs = append(runs, run{class: class, min: 1, max: 1})
+	}
+
+	return runs
+}
+
+func classOf(r rune, mergeLetters bool) string {
+	switch {
+	case r >= '0' && r <= '9':
+		return `\d`
+	case mergeLetters && unicode.IsLetter(r):
+		return `[A-Za-z]`
+	case r >= 'A' && r <= 'Z':
+		return `[A-Z]`
+	case r >= 'a' && r <= 'z':
+		return `[a-z]`
+	default:
+		return regexp.QuoteMeta(string(r))
+	}
+}
+
+func characterPattern(values []string) string {
+	var upper, lower, digit, space bool
+	other := map[rune]struct{}{}
+	min, max := math.MaxInt, 0
+
+	for _, v := range values {
+		length := len([]rune(v))
+		min = lo.Min([]int{min, length})
+		max = lo.Max([]int{max, length})
+
+		for _, r := range v {
+			switch {
+			case r >= 'A' && r <= 'Z':
+				upper = true
+			case r >= 'a' && r <= 'z':
+				lower = true
+			case r >= '0' && r <= '9':
+				digit = true
+			case r == ' ':
+				space = true
+			default:
+				other[r] = struct{}{}
+			}
+		}
+	}
+
+	var class strings.Builder
+	class.WriteString("[")
+	if upper {
+		class.WriteString("A-Z")
+	}
+	if lower {
+		class.WriteString("a-z")
+	}
+	if digit {
+		class.WriteString("0-9")
+	}
+	if space {
+		class.WriteString(" ")
+	}
+
+	others := lo.Keys(other)
+	sort.Slice(others, func(i, j int) bool {
+		return others[i] < others[j]
+	})
+	for _, r := range others {
+		// Escape characters that would otherwise be mistaken for part of
+		// the class syntax.
+		if strings.ContainsRune(`\[]^-`, r) {
+			class.WriteRune('\\')
+		}
+		class.WriteRune(r)
+	}
+	class.WriteString("]")
+
+	return class.String() + quantifier(min, max)
+}
+
+// maxRepeat is the largest repeat count that Go's regexp package supports.
+const maxRepeat = 1000
+
+// quantifier returns a regex quantifier for a run of min to max characters,
+// clamping both bounds to maxRepeat.
+func quantifier(min, max int) string {
+	min = lo.Min([]int{min, maxRepeat})
+	max = lo.Min([]int{max, maxRepeat})
+
+	switch {
+	case min == 1 && max == 1:
+		return ""
+	case min == max:
+		return fmt.Sprintf("{%d}", min)
+	default:
+		return fmt.Sprintf("{%d,%d}", min, max)
+	}
+}
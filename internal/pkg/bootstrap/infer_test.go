@@ -0,0 +1,180 @@
+package bootstrap
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/codingconcepts/dg/internal/pkg/generator"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInferConfig(t *testing.T) {
+	sample := `id,uuid,email,first_name,status,sku,created,notes,age,empty
+1,0b9e6a8e-3a4f-4b0e-9a59-1f3c8d0c6a11,a@b.com,Alice,active,AB-1234,2023-01-01,Hello there,34,
+2,1c8f7b9f-4b5a-4c1f-8b6a-2a4d9e1d7b22,c@d.com,Bob,active,XY-98,2023-01-05,Hi,19,
+3,2d9a8c0a-5c6b-4d2a-9c7b-3b5e0f2e8c33,e@f.com,,inactive,CD-567,2023-01-03,,,
+4,3e0b9d1b-6d7c-4e3b-8d8c-4c6f1a3f9d44,g@h.com,Dan,active,EF-12,2023-01-02,Good day!,72,
+`
+
+	buf := &bytes.Buffer{}
+	err := InferConfig(buf, []Sample{{Name: "person", Data: strings.NewReader(sample)}}, 2)
+	assert.NoError(t, err)
+
+	exp := `tables:
+  - name: person
+    count: 4
+    columns:
+      - name: id
+        type: range
+        processor:
+          type: int
+          from: "1"
+          to: "4"
+          step: "1"
+      - name: uuid
+        type: gen
+        processor:
+          value: ${uuid}
+      - name: email
+        type: gen
+        processor:
+          value: ${email}
+      - name: first_name
+        type: gen
+        processor:
+          value: ${first_name}
+          null_percentage: 25
+      - name: status
+        type: set
+        processor:
+          values: [active, inactive]
+          weights: [3, 1]
+      - name: sku
+        type: gen
+        processor:
+          pattern: '[A-Z]{2}-\d{2,4}'
+      - name: created
+        type: range
+        processor:
+          type: date
+          from: "2023-01-01"
+          to: "2023-01-05"
+          format: "2006-01-02"
+      - name: notes
+        type: gen
+        processor:
+          pattern: '[A-Za-z !]{2,11}'
+          null_percentage: 25
+      - name: age
+        type: dist
+        processor:
+          type: uniform
+          min: 19
+          max: 72
+          precision: 0
+        transform:
+          null_percentage: 25
+      - name: empty
+        type: const
+        processor:
+          values: [""]
+`
+
+	assert.Equal(t, exp, buf.String())
+}
+
+func TestProfileColumnProcessor(t *testing.T) {
+	cases := []struct {
+		name         string
+		values       []string
+		maxSetSize   int
+		expType      string
+		expProcessor any
+	}{
+		{
+			name:         "weighted set with nulls",
+			values:       []string{"a", "b", "a", "", "a"},
+			maxSetSize:   5,
+			expType:      "set",
+			expProcessor: generator.SetGenerator{Values: []string{"a", "b", ""}, Weights: []int{3, 1, 1}},
+		},
+		{
+			name:         "sequence with step",
+			values:       []string{"10", "20", "30"},
+			expType:      "range",
+			expProcessor: generator.RangeGenerator{Type: "int", From: "10", To: "30", Step: "10"},
+		},
+		{
+			name:         "timestamp range",
+			values:       []string{"2023-01-02T10:00:00Z", "2023-01-01T09:00:00Z"},
+			expType:      "range",
+			expProcessor: generator.RangeGenerator{Type: "date", From: "2023-01-01T09:00:00Z", To: "2023-01-02T10:00:00Z", Format: "2006-01-02T15:04:05.999999999Z07:00"},
+		},
+		{
+			name:         "numbers",
+			values:       []string{"12.50", "3.99", "100.00"},
+			expType:      "dist",
+			expProcessor: generator.DistGenerator{Type: "uniform", Min: lo.ToPtr(3.99), Max: lo.ToPtr(100.0), Precision: lo.ToPtr(2)},
+		},
+		{
+			name:         "integers",
+			values:       []string{"35", "19", "", "72"},
+			expType:      "dist",
+			expProcessor: generator.DistGenerator{Type: "uniform", Min: lo.ToPtr(19.0), Max: lo.ToPtr(72.0), Precision: lo.ToPtr(0)},
+		},
+		{
+			name:         "negative numbers",
+			values:       []string{"-1.5", "0", "2.25"},
+			expType:      "dist",
+			expProcessor: generator.DistGenerator{Type: "uniform", Min: lo.ToPtr(-1.5), Max: lo.ToPtr(2.25), Precision: lo.ToPtr(2)},
+		},
+		{
+			name:         "numeric codes",
+			values:       []string{"007", "123", "042"},
+			expType:      "gen",
+			expProcessor: generator.GenGenerator{Pattern: `\d{3}`},
+		},
+		{
+			name:         "mixed case letters",
+			values:       []string{"McDonald", "Smith"},
+			expType:      "gen",
+			expProcessor: generator.GenGenerator{Pattern: `[A-Za-z]{5,8}`},
+		},
+		{
+			name:         "free text",
+			values:       []string{"Hello there", "Hi!"},
+			expType:      "gen",
+			expProcessor: generator.GenGenerator{Pattern: `[A-Za-z !]{3,11}`},
+		},
+		{
+			name:         "long runs",
+			values:       []string{strings.Repeat("a", 1500) + "-1", strings.Repeat("b", 1200) + "-2"},
+			expType:      "gen",
+			expProcessor: generator.GenGenerator{Pattern: `[a-z]{1000}-\d`},
+		},
+		{
+			name:         "long free text",
+			values:       []string{strings.Repeat("ab ", 600), strings.Repeat("c", 1100) + "!"},
+			expType:      "gen",
+			expProcessor: generator.GenGenerator{Pattern: `[a-z !]{1000}`},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actType, actProcessor := profileColumn("col", c.values).processor(c.maxSetSize)
+
+			assert.Equal(t, c.expType, actType)
+			assert.Equal(t, c.expProcessor, actProcessor)
+
+			// Patterns must be valid for dg to load the config.
+			if g, ok := actProcessor.(generator.GenGenerator); ok && g.Pattern != "" {
+				_, err := regexp.Compile(g.Pattern)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
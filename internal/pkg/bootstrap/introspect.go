@@ -0,0 +1,325 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// catalog holds the raw results of querying a database's catalog.
+type catalog struct {
+	tables      []string
+	counts      map[string]int
+	columns     []catalogColumn
+	constraints []catalogConstraint
+	enums       map[string][]string
+}
+
+type catalogColumn struct {
+	table     string
+	name      string
+	dataType  string
+	udtName   string
+	length    *int
+	precision *int
+	scale     *int
+	nullable  bool
+	def       *string
+	identity  bool
+	generated bool
+}
+
+type catalogConstraint struct {
+	table      string
+	kind       string
+	columns    []string
+	refTable   string
+	refColumns []string
+	definition string
+}
+
+// Introspect reads the tables, columns, constraints, enum types, and row
+// counts of a schema in a running Postgres or CockroachDB database.
+func Introspect(ctx context.Context, url, schema string) ([]Table, error) {
+	conn, err := pgx.Connect(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to database: %w", err)
+	}
+	defer conn.Close(ctx)
+
+	c := catalog{}
+	if c.tables, err = queryTables(ctx, conn, schema); err != nil {
+		return nil, fmt.Errorf("querying tables: %w", err)
+	}
+
+	if c.counts, err = queryCounts(ctx, conn, schema, c.tables); err != nil {
+		return nil, fmt.Errorf("querying row counts: %w", err)
+	}
+
+	if c.columns, err = queryColumns(ctx, conn, schema); err != nil {
+		return nil, fmt.Errorf("querying columns: %w", err)
+	}
+
+	if c.constraints, err = queryConstraints(ctx, conn, schema); err != nil {
+		return nil, fmt.Errorf("querying constraints: %w", err)
+	}
+
+	if c.enums, err = queryEnums(ctx, conn, schema); err != nil {
+		return nil, fmt.Errorf("querying enums: %w", err)
+	}
+
+	return c.build(), nil
+}
+
+func queryTables(ctx context.Context, conn *pgx.Conn, schema string) ([]string, error) {
+	const stmt = `
+		SELECT table_name::TEXT
+		FROM information_schema.tables
+		WHERE table_schema = $1
+		AND table_type = 'BASE TABLE'
+		ORDER BY table_name`
+
+	rows, err := conn.Query(ctx, stmt, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// queryCounts uses the planner's row estimates where they're available,
+// falling back to counting the rows of tables that haven't been analyzed.
+func queryCounts(ctx context.Context, conn *pgx.Conn, schema string, tables []string) (map[string]int, error) {
+	const stmt = `
+		SELECT c.relname::TEXT, c.reltuples::BIGINT
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1
+		AND c.relkind IN ('r', 'p')`
+
+	rows, err := conn.Query(ctx, stmt, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	var name string
+	var estimate int
+	if _, err = pgx.ForEachRow(rows, []any{&name, &estimate}, func() error {
+		counts[name] = estimate
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, table := range tables {
+		if counts[table] > 0 {
+			continue
+		}
+
+		stmt := fmt.Sprintf("SELECT count(*) FROM %s", pgx.Identifier{schema, table}.Sanitize())
+
+		var count int
+		if err = conn.QueryRow(ctx, stmt).Scan(&count); err != nil {
+			return nil, fmt.Errorf("counting rows in %q: %w", table, err)
+		}
+		counts[table] = count
+	}
+
+	return counts, nil
+}
+
+func queryColumns(ctx context.Context, conn *pgx.Conn, schema string) ([]catalogColumn, error) {
+	const stmt = `
+		SELECT
+			table_name::TEXT,
+			column_name::TEXT,
+			data_type::TEXT,
+			udt_name::TEXT,
+			character_maximum_length::INT,
+			numeric_precision::INT,
+			numeric_scale::INT,
+			is_nullable::TEXT = 'YES',
+			column_default::TEXT,
+			COALESCE(is_identity::TEXT, 'NO') = 'YES',
+			COALESCE(is_generated::TEXT, 'NEVER') = 'ALWAYS'
+		FROM information_schema.columns
+		WHERE table_schema = $1
+		ORDER BY table_name, ordinal_position`
+
+	rows, err := conn.Query(ctx, stmt, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (catalogColumn, error) {
+		var c catalogColumn
+		err := row.Scan(&c.table, &c.name, &c.dataType, &c.udtName, &c.length, &c.precision, &c.scale, &c.nullable, &c.def, &c.identity, &c.generated)
+		return c, err
+	})
+}
+
+func queryConstraints(ctx context.Context, conn *pgx.Conn, schema string) ([]catalogConstraint, error) {
+	const stmt = `
+		SELECT
+			rel.relname::TEXT,
+			con.contype::TEXT,
+			ARRAY(
+				SELECT a.attname::TEXT
+				FROM unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+				JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+				ORDER BY k.ord
+			),
+			COALESCE(frel.relname::TEXT, ''),
+			ARRAY(
+				SELECT a.attname::TEXT
+				FROM unnest(con.confkey) WITH ORDINALITY AS k(attnum, ord)
+				JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum
+				ORDER BY k.ord
+			),
+			pg_get_constraintdef(con.oid)
+		FROM pg_constraint con
+		JOIN pg_class rel ON rel.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = rel.relnamespace
+		LEFT JOIN pg_class frel ON frel.oid = con.confrelid
+		WHERE n.nspname = $1
+		AND con.contype IN ('p', 'u', 'f', 'c')
+		ORDER BY rel.relname, con.conname`
+
+	rows, err := conn.Query(ctx, stmt, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (catalogConstraint, error) {
+		var c catalogConstraint
+		err := row.Scan(&c.table, &c.kind, &c.columns, &c.refTable, &c.refColumns, &c.definition)
+		return c, err
+	})
+}
+
+func queryEnums(ctx context.Context, conn *pgx.Conn, schema string) (map[string][]string, error) {
+	const stmt = `
+		SELECT t.typname::TEXT, e.enumlabel::TEXT
+		FROM pg_type t
+		JOIN pg_enum e ON e.enumtypid = t.oid
+		JOIN pg_namespace n ON n.oid = t.typnamespace
+		WHERE n.nspname = $1
+		ORDER BY t.typname, e.enumsortorder`
+
+	rows, err := conn.Query(ctx, stmt, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	enums := map[string][]string{}
+	var name, label string
+	if _, err = pgx.ForEachRow(rows, []any{&name, &label}, func() error {
+		enums[name] = append(enums[name], label)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return enums, nil
+}
+
+var checkValueRegex = regexp.MustCompile(`'((?:[^']|'')*)'`)
+
+// build assembles the catalog query results into tables.
+func (c catalog) build() []Table {
+	tables := make([]Table, len(c.tables))
+	indexes := map[string]int{}
+
+	for i, name := range c.tables {
+		tables[i] = Table{Name: name, Count: c.counts[name]}
+		indexes[name] = i
+	}
+
+	for _, cc := range c.columns {
+		i, ok := indexes[cc.table]
+		if !ok {
+			continue
+		}
+
+		col := Column{
+			Name:     cc.name,
+			Type:     cc.udtName,
+			Nullable: cc.nullable,
+			Computed: cc.generated,
+			Values:   c.enums[cc.udtName],
+		}
+
+		switch {
+		case cc.dataType == "ARRAY":
+			col.Type = "array"
+		case cc.length != nil:
+			col.Length = *cc.length
+		case cc.precision != nil && strings.EqualFold(cc.udtName, "numeric"):
+			col.Length = *cc.precision
+			if cc.scale != nil {
+				col.Scale = *cc.scale
+			}
+		}
+
+		if cc.identity || (cc.def != nil && (strings.Contains(*cc.def, "nextval(") || strings.Contains(*cc.def, "unique_rowid()"))) {
+			col.AutoIncrement = true
+		}
+
+		tables[i].Columns = append(tables[i].Columns, col)
+	}
+
+	for _, con := range c.constraints {
+		i, ok := indexes[con.table]
+		if !ok {
+			continue
+		}
+		t := &tables[i]
+
+		switch con.kind {
+		case "p":
+			t.PrimaryKey = con.columns
+			if len(con.columns) == 1 {
+				if col := t.column(con.columns[0]); col != nil {
+					col.PrimaryKey = true
+				}
+			}
+
+		case "u":
+			t.Unique = append(t.Unique, con.columns)
+			if len(con.columns) == 1 {
+				if col := t.column(con.columns[0]); col != nil {
+					col.Unique = true
+				}
+			}
+
+		case "f":
+			for j, name := range con.columns {
+				col := t.column(name)
+				if col == nil || j >= len(con.refColumns) {
+					continue
+				}
+				col.Reference = &Reference{Table: con.refTable, Column: con.refColumns[j]}
+			}
+
+		case "c":
+			if len(con.columns) != 1 || !(strings.Contains(con.definition, " IN ") || strings.Contains(con.definition, "ANY (ARRAY[")) {
+				continue
+			}
+
+			col := t.column(con.columns[0])
+			if col == nil {
+				continue
+			}
+
+			for _, m := range checkValueRegex.FindAllStringSubmatch(con.definition, -1) {
+				col.Values = append(col.Values, strings.ReplaceAll(m[1], "''", "'"))
+			}
+		}
+	}
+
+	return tables
+}
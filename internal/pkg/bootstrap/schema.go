@@ -0,0 +1,41 @@
+package bootstrap
+
+// Table describes a database table from which a dg table can be configured.
+type Table struct {
+	Name       string
+	Count      int
+	Columns    []Column
+	PrimaryKey []string
+	Unique     [][]string
+}
+
+// Column describes a database column from which a dg column can be configured.
+type Column struct {
+	Name          string
+	Type          string
+	Length        int
+	Scale         int
+	Values        []string
+	Nullable      bool
+	PrimaryKey    bool
+	Unique        bool
+	AutoIncrement bool
+	Computed      bool
+	Reference     *Reference
+}
+
+// Reference describes a foreign key from one column to another.
+type Reference struct {
+	Table  string
+	Column string
+}
+
+func (t *Table) column(name string) *Column {
+	for i := range t.Columns {
+		if t.Columns[i].Name == name {
+			return &t.Columns[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package diagram
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codingconcepts/dg/internal/pkg/generator"
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/samber/lo"
+)
+
+// Graph describes the tables and inputs of a config and the columns that
+// connect them.
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+}
+
+// Node is a table or an input.
+type Node struct {
+	Name       string
+	Input      bool
+	Attributes []Attribute
+}
+
+// Attribute is a column of a node, along with the type of generator that
+// populates it (or the input type for input columns).
+type Attribute struct {
+	Name string
+	Type string
+}
+
+// Edge connects a column of one table to the table it takes its values from.
+type Edge struct {
+	From   string
+	To     string
+	Type   string
+	Column string
+	Target string
+
+	// On describes the columns compared by a match (e.g. "market = code").
+	On string
+
+	// Distinct is set for refs that reference each row at most once.
+	Distinct bool
+}
+
+// Build derives a Graph from a config. Tables are connected to the tables
+// and inputs referenced by their ref, each, and match columns, and to
+// themselves by their tree columns.
+func Build(c model.Config) (Graph, error) {
+	var g Graph
+	inputs := map[string]int{}
+
+	for _, input := range c.Inputs {
+		inputs[input.Name] = len(g.Nodes)
+		g.Nodes = append(g.Nodes, Node{Name: input.Name, Input: true})
+	}
+
+	// Input columns aren't known without reading the input, so describe the
+	// ones that tables refer to.
+	addInputColumn := func(table, column string) {
+		i, ok := inputs[table]
+		if !ok {
+			return
+		}
+
+		n := &g.Nodes[i]
+		if !lo.ContainsBy(n.Attributes, func(a Attribute) bool { return a.Name == column }) {
+			n.Attributes = append(n.Attributes, Attribute{Name: column, Type: c.Inputs[i].Type})
+		}
+	}
+
+	for _, t := range c.Tables {
+		n := Node{Name: t.Name}
+
+		for _, col := range t.Columns {
+			n.Attributes = append(n.Attributes, Attribute{Name: col.Name, Type: col.Type})
+
+			switch col.Type {
+			case "ref":
+				var rg generator.RefGenerator
+				if err := col.Generator.UnmarshalFunc(&rg); err != nil {
+					return Graph{}, fmt.Errorf("parsing ref process for %s.%s: %w", t.Name, col.Name, err)
+				}
+
+				// Refs can also copy additional columns from the same row,
+				// which together form a composite key.
+				names, err := rg.Names(col.Name)
+				if err != nil {
+					return Graph{}, fmt.Errorf("parsing ref process for %s.%s: %w", t.Name, col.Name, err)
+				}
+				targets := append([]string{rg.Column}, lo.Map(rg.Columns, func(rc generator.RefColumn, _ int) string {
+					return rc.Column
+				})...)
+
+				for _, name := range names[1:] {
+					n.Attributes = append(n.Attributes, Attribute{Name: name, Type: col.Type})
+				}
+
+				g.Edges = append(g.Edges, Edge{
+					From:     t.Name,
+					To:       rg.Table,
+					Type:     col.Type,
+					Column:   strings.Join(names, ", "),
+					Target:   strings.Join(targets, ", "),
+					Distinct: rg.Distinct,
+				})
+				for _, target := range targets {
+					addInputColumn(rg.Table, target)
+				}
+
+			case "each":
+				var eg generator.EachGenerator
+				if err := col.Generator.UnmarshalFunc(&eg); err != nil {
+					return Graph{}, fmt.Errorf("parsing each process for %s.%s: %w", t.Name, col.Name, err)
+				}
+
+				g.Edges = append(g.Edges, Edge{From: t.Name, To: eg.Table, Type: col.Type, Column: col.Name, Target: eg.Column})
+				addInputColumn(eg.Table, eg.Column)
+
+			case "match":
+				var mg generator.MatchGenerator
+				if err := col.Generator.UnmarshalFunc(&mg); err != nil {
+					return Graph{}, fmt.Errorf("parsing match process for %s.%s: %w", t.Name, col.Name, err)
+				}
+
+				g.Edges = append(g.Edges, Edge{
+					From:   t.Name,
+					To:     mg.SourceTable,
+					Type:   col.Type,
+					Column: col.Name,
+					Target: mg.SourceValue,
+					On:     fmt.Sprintf("%s = %s", mg.MatchColumn, mg.SourceColumn),
+				})
+				addInputColumn(mg.SourceTable, mg.SourceColumn)
+				addInputColumn(mg.SourceTable, mg.SourceValue)
+
+			case "tree":
+				var tg generator.TreeGenerator
+				if err := col.Generator.UnmarshalFunc(&tg); err != nil {
+					return Graph{}, fmt.Errorf("parsing tree process for %s.%s: %w", t.Name, col.Name, err)
+				}
+
+				g.Edges = append(g.Edges, Edge{From: t.Name, To: t.Name, Type: col.Type, Column: col.Name, Target: tg.Column})
+
+				// Trees can also generate depth and path columns.
+				for _, name := range []string{tg.DepthColumn, tg.PathColumn} {
+					if name != "" {
+						n.Attributes = append(n.Attributes, Attribute{Name: name, Type: col.Type})
+					}
+				}
+			}
+		}
+
+		g.Nodes = append(g.Nodes, n)
+	}
+
+	return g, nil
+}
+
+// Label describes the columns an edge connects (e.g. "ref person_id -> id").
+func (e Edge) Label() string {
+	label := fmt.Sprintf("%s %s -> %s", e.Type, e.Column, e.Target)
+	if e.On != "" {
+		label += " on " + e.On
+	}
+
+	return label
+}
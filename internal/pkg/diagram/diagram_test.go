@@ -0,0 +1,300 @@
+package diagram
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const config = `
+inputs:
+  - name: market
+    type: csv
+    source:
+      file_name: market.csv
+
+tables:
+  - name: person
+    count: 10
+    columns:
+      - name: id
+        type: gen
+        processor:
+          value: ${uuid}
+  - name: pet
+    count: 20
+    columns:
+      - name: person_id
+        type: ref
+        processor:
+          table: person
+          column: id
+  - name: person_market
+    columns:
+      - name: person_id
+        type: each
+        processor:
+          table: person
+          column: id
+      - name: market
+        type: each
+        processor:
+          table: market
+          column: code
+      - name: region
+        type: match
+        processor:
+          source_table: market
+          source_column: code
+          source_value: region
+          match_column: market
+`
+
+func loadGraph(t *testing.T) Graph {
+	c, err := model.LoadConfig(strings.NewReader(config))
+	assert.NoError(t, err)
+
+	g, err := Build(c)
+	assert.NoError(t, err)
+
+	return g
+}
+
+func TestBuild(t *testing.T) {
+	g := loadGraph(t)
+
+	expNodes := []Node{
+		{
+			Name:  "market",
+			Input: true,
+			Attributes: []Attribute{
+				{Name: "code", Type: "csv"},
+				{Name: "region", Type: "csv"},
+			},
+		},
+		{
+			Name:       "person",
+			Attributes: []Attribute{{Name: "id", Type: "gen"}},
+		},
+		{
+			Name:       "pet",
+			Attributes: []Attribute{{Name: "person_id", Type: "ref"}},
+		},
+		{
+			Name: "person_market",
+			Attributes: []Attribute{
+				{Name: "person_id", Type: "each"},
+				{Name: "market", Type: "each"},
+				{Name: "region", Type: "match"},
+			},
+		},
+	}
+	assert.Equal(t, expNodes, g.Nodes)
+
+	expEdges := []Edge{
+		{From: "pet", To: "person", Type: "ref", Column: "person_id", Target: "id"},
+		{From: "person_market", To: "person", Type: "each", Column: "person_id", Target: "id"},
+		{From: "person_market", To: "market", Type: "each", Column: "market", Target: "code"},
+		{From: "person_market", To: "market", Type: "match", Column: "region", Target: "region", On: "market = code"},
+	}
+	assert.Equal(t, expEdges, g.Edges)
+}
+
+func TestBuildTree(t *testing.T) {
+	config := `
+tables:
+  - name: category
+    count: 10
+    columns:
+      - name: id
+        type: inc
+        processor:
+          start: 1
+      - name: parent_id
+        type: tree
+        processor:
+          column: id
+          depth_column: depth
+`
+
+	c, err := model.LoadConfig(strings.NewReader(config))
+	assert.NoError(t, err)
+
+	g, err := Build(c)
+	assert.NoError(t, err)
+
+	expNodes := []Node{
+		{
+			Name: "category",
+			Attributes: []Attribute{
+				{Name: "id", Type: "inc"},
+				{Name: "parent_id", Type: "tree"},
+				{Name: "depth", Type: "tree"},
+			},
+		},
+	}
+	assert.Equal(t, expNodes, g.Nodes)
+	assert.Equal(t, []Edge{{From: "category", To: "category", Type: "tree", Column: "parent_id", Target: "id"}}, g.Edges)
+}
+
+func TestBuildDistinctRef(t *testing.T) {
+	config := `
+tables:
+  - name: person
+    count: 10
+    columns:
+      - name: id
+        type: inc
+        processor:
+          start: 1
+  - name: profile
+    count: 10
+    columns:
+      - name: person_id
+        type: ref
+        processor:
+          table: person
+          column: id
+          distinct: true
+`
+
+	c, err := model.LoadConfig(strings.NewReader(config))
+	assert.NoError(t, err)
+
+	g, err := Build(c)
+	assert.NoError(t, err)
+	assert.Equal(t, []Edge{{From: "profile", To: "person", Type: "ref", Column: "person_id", Target: "id", Distinct: true}}, g.Edges)
+
+	var sb strings.Builder
+	assert.NoError(t, g.Write(&sb, "mermaid"))
+	assert.Contains(t, sb.String(), `profile |o--|| person : "ref person_id -> id"`)
+}
+
+func TestBuildMultiColumnRef(t *testing.T) {
+	config := `
+inputs:
+  - name: address
+    type: csv
+    source:
+      file_name: address.csv
+
+tables:
+  - name: person
+    count: 10
+    columns:
+      - name: city
+        type: ref
+        processor:
+          table: address
+          column: city
+          columns:
+            - column: state
+            - column: zip
+              name: postcode
+`
+
+	c, err := model.LoadConfig(strings.NewReader(config))
+	assert.NoError(t, err)
+
+	g, err := Build(c)
+	assert.NoError(t, err)
+
+	expNodes := []Node{
+		{
+			Name:  "address",
+			Input: true,
+			Attributes: []Attribute{
+				{Name: "city", Type: "csv"},
+				{Name: "state", Type: "csv"},
+				{Name: "zip", Type: "csv"},
+			},
+		},
+		{
+			Name: "person",
+			Attributes: []Attribute{
+				{Name: "city", Type: "ref"},
+				{Name: "state", Type: "ref"},
+				{Name: "postcode", Type: "ref"},
+			},
+		},
+	}
+	assert.Equal(t, expNodes, g.Nodes)
+	assert.Equal(t, []Edge{{From: "person", To: "address", Type: "ref", Column: "city, state, postcode", Target: "city, state, zip"}}, g.Edges)
+}
+
+func TestWrite(t *testing.T) {
+	cases := []struct {
+		format string
+		exp    string
+		expErr string
+	}{
+		{
+			format: "mermaid",
+			exp: `erDiagram
+    market {
+        csv code
+        csv region
+    }
+    person {
+        gen id
+    }
+    pet {
+        ref person_id
+    }
+    person_market {
+        each person_id
+        each market
+        match region
+    }
+    pet }o--|| person : "ref person_id -> id"
+    person_market }|--|| person : "each person_id -> id"
+    person_market }|--|| market : "each market -> code"
+    person_market }o--o| market : "match region -> region on market = code"
+`,
+		},
+		{
+			format: "dot",
+			exp: `digraph dg {
+    rankdir=LR;
+    node [shape=record];
+    "market" [label="{market (input)|code: csv\lregion: csv\l}", style=dashed];
+    "person" [label="{person|id: gen\l}"];
+    "pet" [label="{pet|person_id: ref\l}"];
+    "person_market" [label="{person_market|person_id: each\lmarket: each\lregion: match\l}"];
+    "pet" -> "person" [label="ref person_id -> id"];
+    "person_market" -> "person" [label="each person_id -> id"];
+    "person_market" -> "market" [label="each market -> code"];
+    "person_market" -> "market" [label="match region -> region on market = code", style=dashed];
+}
+`,
+		},
+		{
+			format: "svg",
+			expErr: `invalid diagram format "svg" (expected one of: mermaid, dot)`,
+		},
+	}
+
+	g := loadGraph(t)
+
+	for _, c := range cases {
+		t.Run(c.format, func(t *testing.T) {
+			var sb strings.Builder
+			err := g.Write(&sb, c.format)
+			if c.expErr != "" {
+				assert.EqualError(t, err, c.expErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, c.exp, sb.String())
+		})
+	}
+}
+
+func TestEscaping(t *testing.T) {
+	assert.Equal(t, "order_items", mermaidName("order items"))
+	assert.Equal(t, `\{a\|b\}`, dotRecord("{a|b}"))
+	assert.Equal(t, `"say \"hi\""`, dotID(`say "hi"`))
+}
@@ -0,0 +1,129 @@
+package diagram
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Formats are the diagram formats that a Graph can be written in.
+var Formats = []string{"mermaid", "dot"}
+
+var mermaidNameRegex = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// Write writes the graph in the given format.
+func (g Graph) Write(w io.Writer, format string) error {
+	switch format {
+	case "mermaid":
+		return g.Mermaid(w)
+	case "dot":
+		return g.Dot(w)
+	default:
+		return fmt.Errorf("invalid diagram format %q (expected one of: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// Mermaid writes the graph as a Mermaid entity relationship diagram.
+func (g Graph) Mermaid(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("erDiagram\n")
+
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&sb, "    %s {\n", mermaidName(n.Name))
+		for _, a := range n.Attributes {
+			fmt.Fprintf(&sb, "        %s %s\n", mermaidName(a.Type), mermaidName(a.Name))
+		}
+		sb.WriteString("    }\n")
+	}
+
+	for _, e := range g.Edges {
+		fmt.Fprintf(&sb, "    %s %s %s : %q\n", mermaidName(e.From), mermaidCardinality(e), mermaidName(e.To), e.Label())
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// mermaidCardinality returns the relationship between a table and the table
+// an edge points to:
+//
+//   - ref: many rows refer to at most one row.
+//   - distinct ref: at most one row refers to each row.
+//   - each: every row is referred to by one or more rows.
+//   - match: rows match at most one row.
+//   - tree: rows have at most one parent row.
+func mermaidCardinality(e Edge) string {
+	switch e.Type {
+	case "ref":
+		if e.Distinct {
+			return "|o--||"
+		}
+		return "}o--||"
+	case "each":
+		return "}|--||"
+	default:
+		return "}o--o|"
+	}
+}
+
+// mermaidName replaces characters that Mermaid doesn't allow in entity and
+// attribute names.
+func mermaidName(s string) string {
+	return mermaidNameRegex.ReplaceAllString(s, "_")
+}
+
+// Dot writes the graph as a Graphviz digraph.
+func (g Graph) Dot(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("digraph dg {\n")
+	sb.WriteString("    rankdir=LR;\n")
+	sb.WriteString("    node [shape=record];\n")
+
+	for _, n := range g.Nodes {
+		title := dotRecord(n.Name)
+		style := ""
+		if n.Input {
+			title += ` (input)`
+			style = ", style=dashed"
+		}
+
+		var fields strings.Builder
+		for _, a := range n.Attributes {
+			fmt.Fprintf(&fields, `%s: %s\l`, dotRecord(a.Name), dotRecord(a.Type))
+		}
+
+		fmt.Fprintf(&sb, "    %s [label=\"{%s|%s}\"%s];\n", dotID(n.Name), title, fields.String(), style)
+	}
+
+	for _, e := range g.Edges {
+		style := ""
+		if e.Type == "match" {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&sb, "    %s -> %s [label=%s%s];\n", dotID(e.From), dotID(e.To), dotID(e.Label()), style)
+	}
+
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func dotID(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// dotRecord escapes the characters that have special meaning in a record
+// label, and quotes, as the label is written as a quoted string.
+func dotRecord(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		`{`, `\{`,
+		`}`, `\}`,
+		`|`, `\|`,
+		`<`, `\<`,
+		`>`, `\>`,
+	).Replace(s)
+}
@@ -0,0 +1,628 @@
+package explain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/codingconcepts/dg/internal/pkg/generator"
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/samber/lo"
+)
+
+const (
+	// sampleSize is the number of values generated to estimate the average
+	// width of a column whose values can't be known up front.
+	sampleSize = 1000
+
+	// defaultWidth is assumed for columns whose width can't be estimated.
+	defaultWidth = 8
+
+	// stringSize and sliceSize are the sizes of a string header and a slice
+	// header, which are paid for every value and every row respectively.
+	stringSize = 16
+	sliceSize  = 24
+)
+
+// Thresholds determine when a plan should warn about the size of the data
+// it'll generate. A zero threshold is never exceeded.
+type Thresholds struct {
+	Rows   int
+	Memory int64
+	Output int64
+}
+
+// Plan describes the data that a config will generate.
+type Plan struct {
+	Tables   []Table
+	Memory   int64
+	Output   int64
+	Warnings []string
+}
+
+// Table describes the data that will be generated for a single table.
+type Table struct {
+	Name       string
+	Rows       int
+	Columns    int
+	Memory     int64
+	Output     int64
+	Suppressed bool
+	Notes      []string
+}
+
+// Explain estimates the number of rows, memory, and output size of each of
+// the config's tables (which are expected to be sorted in the order they'll
+// be generated), without generating them. The files map should contain any
+// inputs the config depends on.
+func Explain(c model.Config, files map[string]model.CSVFile, th Thresholds) (Plan, error) {
+	e := estimator{
+		rows:   model.RowCounts(files),
+		widths: map[string]map[string]float64{},
+	}
+
+	for name, file := range files {
+		e.widths[name] = map[string]float64{}
+		for i, column := range file.Header {
+			e.widths[name][column] = averageWidth(file.Lines[i])
+		}
+	}
+
+	var p Plan
+	var retained, transient int64
+
+	for _, t := range c.Tables {
+		et, peak, err := e.table(t)
+		if err != nil {
+			return Plan{}, fmt.Errorf("explaining table %q: %w", t.Name, err)
+		}
+
+		p.Tables = append(p.Tables, et)
+		retained = add(retained, et.Memory-peak)
+		transient = lo.Max([]int64{transient, peak})
+		p.Output = add(p.Output, et.Output)
+
+		if th.Rows > 0 && et.Rows > th.Rows {
+			p.Warnings = append(p.Warnings, fmt.Sprintf("table %q will generate %s rows (threshold %s)", t.Name, FormatCount(et.Rows), FormatCount(th.Rows)))
+		}
+
+		p.Warnings = append(p.Warnings, e.refWarnings(t, et.Rows)...)
+		p.Warnings = append(p.Warnings, e.uniqueWarnings(t, et.Rows)...)
+	}
+	p.Memory = add(retained, transient)
+
+	if th.Memory > 0 && p.Memory > th.Memory {
+		p.Warnings = append(p.Warnings, fmt.Sprintf("generation will use an estimated %s of memory (threshold %s)", FormatBytes(p.Memory), FormatBytes(th.Memory)))
+	}
+
+	if th.Output > 0 && p.Output > th.Output {
+		p.Warnings = append(p.Warnings, fmt.Sprintf("generation will write an estimated %s of csv files (threshold %s)", FormatBytes(p.Output), FormatBytes(th.Output)))
+	}
+
+	return p, nil
+}
+
+// estimator holds the estimated row counts and column widths of the tables
+// explained so far, so that later tables can refer to them.
+type estimator struct {
+	rows   map[string]int
+	widths map[string]map[string]float64
+}
+
+// table returns the estimate for a table, along with the memory that will
+// only be needed while the table is being generated.
+func (e *estimator) table(t model.Table) (Table, int64, error) {
+	rows, notes, err := e.rowCount(t)
+	if err != nil {
+		return Table{}, 0, err
+	}
+
+	widths := map[string]float64{}
+	for _, c := range t.Columns {
+		if widths[c.Name], err = e.width(t, c, rows); err != nil {
+			return Table{}, 0, fmt.Errorf("estimating width of %q: %w", c.Name, err)
+		}
+
+		if err = e.refColumnWidths(c, widths); err != nil {
+			return Table{}, 0, fmt.Errorf("estimating width of %q: %w", c.Name, err)
+		}
+	}
+
+	names := lo.FlatMap(t.Columns, func(c model.Column, _ int) []string {
+		return generator.ColumnNames(c)
+	})
+
+	// Duplicate rows are dropped unless they're regenerated, in which case
+	// generation fails instead (see uniqueWarnings).
+	if limit, ok := e.uniqueLimit(t); ok && limit < rows && t.UniqueMode != "regenerate" {
+		rows = limit
+		notes = append(notes, fmt.Sprintf("unique_columns allow at most %s rows", FormatCount(rows)))
+	}
+
+	et := Table{
+		Name:       t.Name,
+		Rows:       rows,
+		Columns:    len(names),
+		Suppressed: t.Suppress,
+		Notes:      notes,
+	}
+
+	var retained float64
+	for _, name := range names {
+		retained += float64(rows) * (stringSize + widths[name])
+	}
+
+	// Cartesian products and unique_columns both build a row-major copy of
+	// the table while it's being generated.
+	var transient float64
+	eachColumns := len(columnsOfType(t, "each"))
+	if eachColumns > 0 {
+		transient += 2 * float64(rows) * (sliceSize + stringSize*float64(eachColumns))
+	}
+	if len(t.UniqueColumns) > 0 {
+		transient += 2 * float64(rows) * (sliceSize + stringSize*float64(len(names)))
+	}
+
+	et.Memory = toBytes(retained + transient)
+
+	if !t.Suppress {
+		visible := lo.FlatMap(t.Columns, func(c model.Column, _ int) []string {
+			return lo.Ternary(c.Suppress, nil, generator.ColumnNames(c))
+		})
+
+		var header, line float64
+		for _, name := range visible {
+			header += float64(len(name))
+			line += widths[name]
+		}
+
+		// Account for the commas between values and the newline after them.
+		separators := float64(len(visible))
+		et.Output = toBytes(header + separators + float64(rows)*(line+separators))
+	}
+
+	e.rows[t.Name] = rows
+	e.widths[t.Name] = widths
+
+	return et, toBytes(transient), nil
+}
+
+// rowCount mirrors the way the generators determine how many rows to
+// generate: each columns create the Cartesian product of their sources,
+// otherwise the table's count is used, falling back to the number of values
+// in const columns and the length of range columns.
+func (e *estimator) rowCount(t model.Table) (int, []string, error) {
+	if each := columnsOfType(t, "each"); len(each) > 0 {
+		rows := 1
+		var factors []string
+
+		for _, c := range each {
+			var g generator.EachGenerator
+			if err := c.Generator.UnmarshalFunc(&g); err != nil {
+				return 0, nil, fmt.Errorf("parsing each process for %s.%s: %w", t.Name, c.Name, err)
+			}
+
+			n, ok := e.rows[g.Table]
+			if !ok {
+				return 0, nil, fmt.Errorf("missing table %q for each column %q", g.Table, c.Name)
+			}
+
+			rows = multiply(rows, n)
+			factors = append(factors, fmt.Sprintf("%s (%s)", g.Table, FormatCount(n)))
+
+			if g.PerRow != nil {
+				mean, err := meanFanOut(*g.PerRow)
+				if err != nil {
+					return 0, nil, fmt.Errorf("parsing per_row for %s.%s: %w", t.Name, c.Name, err)
+				}
+
+				rows = scale(rows, mean)
+				factors = append(factors, fmt.Sprintf("~%s per row", strconv.FormatFloat(mean, 'f', 1, 64)))
+			}
+		}
+
+		note := fmt.Sprintf("each: %s", strings.Join(factors, " × "))
+		if rows == math.MaxInt {
+			note += ", overflows"
+		}
+
+		return rows, []string{note}, nil
+	}
+
+	rows, err := t.EvaluateCount(e.rows)
+	if err != nil {
+		return 0, nil, fmt.Errorf("evaluating count: %w", err)
+	}
+
+	if rows > 0 {
+		return rows, nil, nil
+	}
+
+	for _, c := range columnsOfType(t, "const") {
+		var g generator.ConstGenerator
+		if err := c.Generator.UnmarshalFunc(&g); err != nil {
+			return 0, nil, fmt.Errorf("parsing const process for %s.%s: %w", t.Name, c.Name, err)
+		}
+		rows = lo.Max([]int{rows, len(g.Values)})
+	}
+
+	if rows > 0 {
+		return rows, nil, nil
+	}
+
+	for _, c := range columnsOfType(t, "range") {
+		var g generator.RangeGenerator
+		if err := c.Generator.UnmarshalFunc(&g); err != nil {
+			return 0, nil, fmt.Errorf("parsing range process for %s.%s: %w", t.Name, c.Name, err)
+		}
+
+		values, err := sample(g, model.Table{Name: t.Name}, c)
+		if err != nil {
+			return 0, nil, fmt.Errorf("running range process for %s.%s: %w", t.Name, c.Name, err)
+		}
+
+		return len(values), nil, nil
+	}
+
+	return 0, nil, nil
+}
+
+// width returns the average width of a column's values.
+func (e *estimator) width(t model.Table, c model.Column, rows int) (float64, error) {
+	switch c.Type {
+	case "const":
+		var g generator.ConstGenerator
+		if err := c.Generator.UnmarshalFunc(&g); err != nil {
+			return 0, fmt.Errorf("parsing const process: %w", err)
+		}
+		return averageWidth(g.Values), nil
+
+	case "set":
+		var g generator.SetGenerator
+		if err := c.Generator.UnmarshalFunc(&g); err != nil {
+			return 0, fmt.Errorf("parsing set process: %w", err)
+		}
+		return weightedWidth(g.Values, g.Weights), nil
+
+	case "inc":
+		var g generator.IncGenerator
+		if err := c.Generator.UnmarshalFunc(&g); err != nil {
+			return 0, fmt.Errorf("parsing inc process: %w", err)
+		}
+
+		// The widest values dominate an incrementing sequence, which are at
+		// whichever end is furthest from zero (including any minus sign).
+		step := lo.Ternary(g.Step == 0, 1, g.Step)
+		last := g.Start + lo.Max([]int{rows - 1, 0})*step
+
+		format := func(v int) float64 {
+			if g.Format != "" {
+				return float64(len(fmt.Sprintf(g.Format, v)))
+			}
+			return float64(len(strconv.Itoa(v)))
+		}
+		return math.Max(format(g.Start), format(last)), nil
+
+	case "gen":
+		var g generator.GenGenerator
+		if err := c.Generator.UnmarshalFunc(&g); err != nil {
+			return 0, fmt.Errorf("parsing gen process: %w", err)
+		}
+
+		// Uniqueness doesn't affect the width of values, and the sample can
+		// be larger than the number of unique values a pattern can produce.
+		g.Unique = false
+
+		values, err := sample(g, model.Table{Name: t.Name, Count: sampleSize}, c)
+		if err != nil {
+			return 0, err
+		}
+		return averageWidth(values), nil
+
+	case "range":
+		var g generator.RangeGenerator
+		if err := c.Generator.UnmarshalFunc(&g); err != nil {
+			return 0, fmt.Errorf("parsing range process: %w", err)
+		}
+
+		values, err := sample(g, model.Table{Name: t.Name, Count: lo.Clamp(rows, 2, sampleSize)}, c)
+		if err != nil {
+			return 0, err
+		}
+		return averageWidth(values), nil
+
+	case "ref", "each":
+		var g generator.RefGenerator
+		if err := c.Generator.UnmarshalFunc(&g); err != nil {
+			return 0, fmt.Errorf("parsing %s process: %w", c.Type, err)
+		}
+		return e.columnWidth(g.Table, g.Column)
+
+	case "match":
+		var g generator.MatchGenerator
+		if err := c.Generator.UnmarshalFunc(&g); err != nil {
+			return 0, fmt.Errorf("parsing match process: %w", err)
+		}
+		width, err := e.columnWidth(g.SourceTable, g.SourceValue)
+		if err != nil || rows == 0 {
+			return width, err
+		}
+
+		// Rows without a match are left empty, and there can be at most as
+		// many matches as there are rows in the source table.
+		matches := lo.Min([]int{e.rows[g.SourceTable], rows})
+		return width * float64(matches) / float64(rows), nil
+
+	default:
+		return defaultWidth, nil
+	}
+}
+
+// refWarnings returns warnings for the ref columns of a table whose limits on
+// how many times each parent can be referenced can't be satisfied by the
+// table's row count, which would fail generation.
+func (e *estimator) refWarnings(t model.Table, rows int) []string {
+	var warnings []string
+
+	for _, c := range columnsOfType(t, "ref") {
+		var g generator.RefGenerator
+		if err := c.Generator.UnmarshalFunc(&g); err != nil {
+			continue
+		}
+
+		parents, ok := e.rows[g.Table]
+		if !ok || parents == 0 {
+			continue
+		}
+
+		minPerParent := g.MinPerParent
+		if g.Coverage == "all" {
+			minPerParent = lo.Max([]int{minPerParent, 1})
+		}
+
+		maxPerParent := g.MaxPerParent
+		if g.Distinct {
+			maxPerParent = 1
+		}
+
+		switch {
+		case g.Distinct && g.MaxPerParent > 1:
+			warnings = append(warnings, fmt.Sprintf("%s.%s can't have a max_per_parent greater than 1, as it's distinct", t.Name, c.Name))
+
+		case maxPerParent != 0 && maxPerParent < minPerParent:
+			warnings = append(warnings, fmt.Sprintf("%s.%s can't reference each parent at least %d and at most %d times", t.Name, c.Name, minPerParent, maxPerParent))
+
+		case rows < parents*minPerParent:
+			warnings = append(warnings, fmt.Sprintf("table %q will generate %s rows, too few for %s to reference each of the %s rows of %q at least %d times", t.Name, FormatCount(rows), c.Name, FormatCount(parents), g.Table, minPerParent))
+
+		case g.Distinct && rows > parents:
+			warnings = append(warnings, fmt.Sprintf("table %q will generate %s rows, too many for %s to reference each of the %s rows of %q at most once", t.Name, FormatCount(rows), c.Name, FormatCount(parents), g.Table))
+
+		case maxPerParent != 0 && rows > parents*maxPerParent:
+			warnings = append(warnings, fmt.Sprintf("table %q will generate %s rows, too many for %s to reference each of the %s rows of %q at most %d times", t.Name, FormatCount(rows), c.Name, FormatCount(parents), g.Table, maxPerParent))
+		}
+	}
+
+	return warnings
+}
+
+// refColumnWidths adds the widths of the additional columns that a ref
+// column copies from the same row.
+func (e *estimator) refColumnWidths(c model.Column, widths map[string]float64) error {
+	if c.Type != "ref" {
+		return nil
+	}
+
+	var g generator.RefGenerator
+	if err := c.Generator.UnmarshalFunc(&g); err != nil {
+		return fmt.Errorf("parsing ref process: %w", err)
+	}
+
+	names, err := g.Names(c.Name)
+	if err != nil {
+		return err
+	}
+
+	for i, rc := range g.Columns {
+		width, err := e.columnWidth(g.Table, rc.Column)
+		if err != nil {
+			return err
+		}
+		widths[names[i+1]] = width
+	}
+
+	return nil
+}
+
+func (e *estimator) columnWidth(table, column string) (float64, error) {
+	widths, ok := e.widths[table]
+	if !ok {
+		return 0, fmt.Errorf("missing table %q", table)
+	}
+
+	width, ok := widths[column]
+	if !ok {
+		return 0, fmt.Errorf("missing column %q in table %q", column, table)
+	}
+
+	return width, nil
+}
+
+// uniqueWarnings returns a warning for a table that regenerates duplicate
+// rows, if its unique_columns don't allow enough distinct combinations of
+// values for all of its rows, as generation would fail.
+func (e *estimator) uniqueWarnings(t model.Table, rows int) []string {
+	if t.UniqueMode != "regenerate" {
+		return nil
+	}
+
+	if limit, ok := e.uniqueLimit(t); ok && limit < rows {
+		return []string{fmt.Sprintf("table %q will generate %s rows, but its unique_columns allow at most %s", t.Name, FormatCount(rows), FormatCount(limit))}
+	}
+
+	return nil
+}
+
+// uniqueLimit returns the maximum number of distinct combinations of a
+// table's unique columns, if every one of them has a known number of values.
+func (e *estimator) uniqueLimit(t model.Table) (int, bool) {
+	if len(t.UniqueColumns) == 0 {
+		return 0, false
+	}
+
+	limit := 1
+	for _, name := range t.UniqueColumns {
+		c, ok := lo.Find(t.Columns, func(c model.Column) bool {
+			return c.Name == name
+		})
+		if !ok {
+			return 0, false
+		}
+
+		n, ok := e.distinctValues(c)
+		if !ok {
+			return 0, false
+		}
+		limit = multiply(limit, n)
+	}
+
+	return limit, true
+}
+
+func (e *estimator) distinctValues(c model.Column) (int, bool) {
+	switch c.Type {
+	case "const":
+		var g generator.ConstGenerator
+		if err := c.Generator.UnmarshalFunc(&g); err != nil {
+			return 0, false
+		}
+		return len(lo.Uniq(g.Values)), true
+
+	case "set":
+		var g generator.SetGenerator
+		if err := c.Generator.UnmarshalFunc(&g); err != nil {
+			return 0, false
+		}
+		return len(lo.Uniq(g.Values)), true
+
+	case "ref", "each":
+		var g generator.RefGenerator
+		if err := c.Generator.UnmarshalFunc(&g); err != nil {
+			return 0, false
+		}
+		n, ok := e.rows[g.Table]
+		return n, ok
+
+	default:
+		return 0, false
+	}
+}
+
+// sample runs a generator against an empty table and returns the values it
+// generated for the column.
+func sample(g interface {
+	Generate(model.Table, model.Column, map[string]model.CSVFile) error
+}, t model.Table, c model.Column) ([]string, error) {
+	files := map[string]model.CSVFile{}
+	if err := g.Generate(t, c, files); err != nil {
+		return nil, err
+	}
+
+	file := files[t.Name]
+	if len(file.Lines) == 0 {
+		return nil, nil
+	}
+	return file.Lines[0], nil
+}
+
+// meanFanOut estimates the average number of rows generated for each source
+// row of an each column, by sampling its fan-out.
+func meanFanOut(d generator.DistGenerator) (float64, error) {
+	fanOut, err := d.FanOut()
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for i := 0; i < sampleSize; i++ {
+		total += fanOut()
+	}
+	return float64(total) / sampleSize, nil
+}
+
+func columnsOfType(t model.Table, typ string) []model.Column {
+	return lo.Filter(t.Columns, func(c model.Column, _ int) bool {
+		return c.Type == typ
+	})
+}
+
+func averageWidth(values []string) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	var total int
+	for _, v := range values {
+		total += len(v)
+	}
+
+	return float64(total) / float64(len(values))
+}
+
+func weightedWidth(values []string, weights []int) float64 {
+	if len(weights) != len(values) {
+		return averageWidth(values)
+	}
+
+	var total, sum float64
+	for i, v := range values {
+		total += float64(len(v) * weights[i])
+		sum += float64(weights[i])
+	}
+
+	if sum == 0 {
+		return 0
+	}
+	return total / sum
+}
+
+// multiply returns the product of two row counts, saturating rather than
+// overflowing.
+func multiply(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+
+	if a > math.MaxInt/b {
+		return math.MaxInt
+	}
+
+	return a * b
+}
+
+// scale returns a row count multiplied by a factor, saturating rather than
+// overflowing.
+func scale(rows int, factor float64) int {
+	if f := math.Round(float64(rows) * factor); f < math.MaxInt {
+		return int(f)
+	}
+
+	return math.MaxInt
+}
+
+func add(a, b int64) int64 {
+	if a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+
+	return a + b
+}
+
+func toBytes(f float64) int64 {
+	if f >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	return int64(f)
+}
@@ -0,0 +1,405 @@
+package explain
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	config := `
+tables:
+  - name: person
+    count: 1000
+    columns:
+      - name: id
+        type: inc
+        processor:
+          start: 1
+  - name: event
+    count: person / 10
+    columns:
+      - name: code
+        type: const
+        processor:
+          values: [abcd]
+  - name: person_event
+    columns:
+      - name: person_id
+        type: each
+        processor:
+          table: person
+          column: id
+      - name: event_code
+        type: each
+        processor:
+          table: event
+          column: code
+      - name: market
+        type: each
+        processor:
+          table: market
+          column: code
+  - name: person_type
+    count: 100
+    suppress: true
+    unique_columns: [a, b]
+    columns:
+      - name: a
+        type: set
+        processor:
+          values: [x, y, x]
+      - name: b
+        type: ref
+        processor:
+          table: market
+          column: code
+`
+
+	c, err := model.LoadConfig(strings.NewReader(config))
+	assert.NoError(t, err)
+
+	files := map[string]model.CSVFile{
+		"market": {
+			Name:   "market",
+			Header: []string{"code"},
+			Lines:  [][]string{{"uk", "us"}},
+		},
+	}
+
+	p, err := Explain(c, files, Thresholds{Rows: 100000})
+	assert.NoError(t, err)
+
+	exp := []Table{
+		{Name: "person", Rows: 1000, Columns: 1, Memory: 20000, Output: 5003},
+		{Name: "event", Rows: 100, Columns: 1, Memory: 2000, Output: 505},
+		{
+			Name:    "person_event",
+			Rows:    200000,
+			Columns: 3,
+			Memory:  40400000,
+			Output:  2600028,
+			Notes:   []string{"each: person (1,000) × event (100) × market (2)"},
+		},
+		{
+			Name:       "person_type",
+			Rows:       4,
+			Columns:    2,
+			Memory:     588,
+			Suppressed: true,
+			Notes:      []string{"unique_columns allow at most 4 rows"},
+		},
+	}
+	assert.Equal(t, exp, p.Tables)
+
+	// Retained memory for every table, plus the Cartesian product of the
+	// largest table while it's being generated.
+	assert.Equal(t, int64(20000+2000+11600000+140+28800000), p.Memory)
+	assert.Equal(t, int64(5003+505+2600028), p.Output)
+	assert.Equal(t, []string{`table "person_event" will generate 200,000 rows (threshold 100,000)`}, p.Warnings)
+}
+
+func TestExplainMissingTable(t *testing.T) {
+	config := `
+tables:
+  - name: pet
+    count: 10
+    columns:
+      - name: person_id
+        type: ref
+        processor:
+          table: person
+          column: id
+`
+
+	c, err := model.LoadConfig(strings.NewReader(config))
+	assert.NoError(t, err)
+
+	_, err = Explain(c, map[string]model.CSVFile{}, Thresholds{})
+	assert.EqualError(t, err, `explaining table "pet": estimating width of "person_id": missing table "person"`)
+}
+
+func TestExplainPerRow(t *testing.T) {
+	config := `
+tables:
+  - name: order
+    count: 100
+    columns:
+      - name: id
+        type: inc
+        processor:
+          start: 1
+  - name: line_item
+    columns:
+      - name: order_id
+        type: each
+        processor:
+          table: order
+          column: id
+          per_row:
+            min: 3
+            max: 3
+`
+
+	c, err := model.LoadConfig(strings.NewReader(config))
+	assert.NoError(t, err)
+
+	p, err := Explain(c, map[string]model.CSVFile{}, Thresholds{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 300, p.Tables[1].Rows)
+	assert.Equal(t, []string{"each: order (100) × ~3.0 per row"}, p.Tables[1].Notes)
+}
+
+func TestExplainRefColumns(t *testing.T) {
+	config := `
+tables:
+  - name: store
+    count: 10
+    columns:
+      - name: region
+        type: const
+        processor:
+          values: [north]
+      - name: number
+        type: inc
+        processor:
+          start: 1
+  - name: sale
+    count: 100
+    columns:
+      - name: store_region
+        type: ref
+        processor:
+          table: store
+          column: region
+          columns:
+            - column: number
+              name: store_number
+`
+
+	c, err := model.LoadConfig(strings.NewReader(config))
+	assert.NoError(t, err)
+
+	p, err := Explain(c, map[string]model.CSVFile{}, Thresholds{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, p.Tables[1].Columns)
+
+	// "store_region,store_number\n" followed by rows like "north,10\n".
+	assert.Equal(t, int64(26+100*9), p.Tables[1].Output)
+}
+
+func TestExplainIncWidth(t *testing.T) {
+	cases := []struct {
+		name      string
+		processor string
+		expWidth  int64
+	}{
+		{name: "default step", processor: "start: 0", expWidth: 2},
+		{name: "step", processor: "start: 1\n          step: 10", expWidth: 3},
+		{name: "negative step", processor: "start: 3\n          step: -1", expWidth: 3},
+		{name: "negative values", processor: "start: 0\n          step: -10", expWidth: 4},
+		{name: "start wider than last", processor: "start: 10000\n          step: -100", expWidth: 5},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config := `
+tables:
+  - name: person
+    count: 100
+    columns:
+      - name: id
+        type: inc
+        processor:
+          ` + c.processor + `
+`
+
+			cfg, err := model.LoadConfig(strings.NewReader(config))
+			assert.NoError(t, err)
+
+			p, err := Explain(cfg, map[string]model.CSVFile{}, Thresholds{})
+			assert.NoError(t, err)
+
+			// "id\n" followed by 100 rows of values and newlines.
+			assert.Equal(t, 3+100*(c.expWidth+1), p.Tables[0].Output)
+		})
+	}
+}
+
+func TestExplainUniqueGenWidth(t *testing.T) {
+	config := `
+tables:
+  - name: person
+    count: 20
+    columns:
+      - name: initial
+        type: gen
+        processor:
+          pattern: '[a-z]'
+          unique: true
+`
+
+	cfg, err := model.LoadConfig(strings.NewReader(config))
+	assert.NoError(t, err)
+
+	p, err := Explain(cfg, map[string]model.CSVFile{}, Thresholds{})
+	assert.NoError(t, err)
+
+	// "initial\n" followed by 20 rows of single characters and newlines.
+	assert.Equal(t, int64(8+20*2), p.Tables[0].Output)
+}
+
+func TestExplainRefWarnings(t *testing.T) {
+	cases := []struct {
+		name      string
+		count     int
+		processor string
+		exp       []string
+	}{
+		{
+			name:      "satisfiable",
+			count:     10,
+			processor: "distinct: true\n          coverage: all",
+		},
+		{
+			name:      "too many rows for distinct",
+			count:     11,
+			processor: "distinct: true",
+			exp:       []string{`table "pet" will generate 11 rows, too many for person_id to reference each of the 10 rows of "person" at most once`},
+		},
+		{
+			name:      "too few rows for min per parent",
+			count:     15,
+			processor: "min_per_parent: 2",
+			exp:       []string{`table "pet" will generate 15 rows, too few for person_id to reference each of the 10 rows of "person" at least 2 times`},
+		},
+		{
+			name:      "too few rows for coverage",
+			count:     5,
+			processor: "coverage: all",
+			exp:       []string{`table "pet" will generate 5 rows, too few for person_id to reference each of the 10 rows of "person" at least 1 times`},
+		},
+		{
+			name:      "too many rows for max per parent",
+			count:     31,
+			processor: "max_per_parent: 3",
+			exp:       []string{`table "pet" will generate 31 rows, too many for person_id to reference each of the 10 rows of "person" at most 3 times`},
+		},
+		{
+			name:      "max per parent less than min per parent",
+			count:     20,
+			processor: "min_per_parent: 3\n          max_per_parent: 2",
+			exp:       []string{"pet.person_id can't reference each parent at least 3 and at most 2 times"},
+		},
+		{
+			name:      "distinct with max per parent",
+			count:     5,
+			processor: "distinct: true\n          max_per_parent: 2",
+			exp:       []string{"pet.person_id can't have a max_per_parent greater than 1, as it's distinct"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config := fmt.Sprintf(`
+tables:
+  - name: person
+    count: 10
+    columns:
+      - name: id
+        type: inc
+        processor:
+          start: 1
+  - name: pet
+    count: %d
+    columns:
+      - name: person_id
+        type: ref
+        processor:
+          table: person
+          column: id
+          %s
+`, c.count, c.processor)
+
+			cfg, err := model.LoadConfig(strings.NewReader(config))
+			assert.NoError(t, err)
+
+			p, err := Explain(cfg, map[string]model.CSVFile{}, Thresholds{})
+			assert.NoError(t, err)
+			assert.Equal(t, c.exp, p.Warnings)
+		})
+	}
+}
+
+func TestExplainUniqueMode(t *testing.T) {
+	cases := []struct {
+		name        string
+		mode        string
+		count       int
+		expRows     int
+		expNotes    []string
+		expWarnings []string
+	}{
+		{
+			name:     "drop",
+			mode:     "drop",
+			count:    10,
+			expRows:  6,
+			expNotes: []string{"unique_columns allow at most 6 rows"},
+		},
+		{
+			name:        "regenerate",
+			mode:        "regenerate",
+			count:       10,
+			expRows:     10,
+			expWarnings: []string{`table "person" will generate 10 rows, but its unique_columns allow at most 6`},
+		},
+		{
+			name:    "regenerate satisfiable",
+			mode:    "regenerate",
+			count:   6,
+			expRows: 6,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config := fmt.Sprintf(`
+tables:
+  - name: person
+    count: %d
+    unique_columns: [a, b]
+    unique_mode: %s
+    columns:
+      - name: a
+        type: set
+        processor:
+          values: [a, b, c]
+      - name: b
+        type: set
+        processor:
+          values: [a, b]
+`, c.count, c.mode)
+
+			cfg, err := model.LoadConfig(strings.NewReader(config))
+			assert.NoError(t, err)
+
+			p, err := Explain(cfg, map[string]model.CSVFile{}, Thresholds{})
+			assert.NoError(t, err)
+			assert.Equal(t, c.expRows, p.Tables[0].Rows)
+			assert.Equal(t, c.expNotes, p.Tables[0].Notes)
+			assert.Equal(t, c.expWarnings, p.Warnings)
+		})
+	}
+}
+
+func TestMultiply(t *testing.T) {
+	assert.Equal(t, 6, multiply(2, 3))
+	assert.Equal(t, 0, multiply(0, 3))
+	assert.Equal(t, int(^uint(0)>>1), multiply(1<<40, 1<<40))
+}
@@ -0,0 +1,107 @@
+package explain
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+var units = []string{"B", "KB", "MB", "GB", "TB", "PB", "EB"}
+
+// Write prints the plan as a table, followed by any warnings.
+func (p Plan) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "#\tTABLE\tROWS\tCOLUMNS\tMEMORY\tOUTPUT\tNOTES")
+	for i, t := range p.Tables {
+		output := FormatBytes(t.Output)
+		if t.Suppressed {
+			output = "-"
+		}
+
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%s\t%s\t%s\n",
+			i+1, t.Name, FormatCount(t.Rows), t.Columns, FormatBytes(t.Memory), output, strings.Join(t.Notes, "; "))
+	}
+	fmt.Fprintf(tw, "\ttotal\t\t\t%s\t%s\t\n", FormatBytes(p.Memory), FormatBytes(p.Output))
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("writing plan: %w", err)
+	}
+
+	for _, warning := range p.Warnings {
+		if _, err := fmt.Fprintf(w, "\nwarning: %s", warning); err != nil {
+			return fmt.Errorf("writing warnings: %w", err)
+		}
+	}
+
+	if len(p.Warnings) > 0 {
+		_, err := fmt.Fprintln(w)
+		return err
+	}
+
+	return nil
+}
+
+// FormatCount returns a number with thousands separators (e.g. 1,000,000).
+func FormatCount(n int) string {
+	s := strconv.Itoa(n)
+
+	var sb strings.Builder
+	for i, r := range s {
+		if i > 0 && (len(s)-i)%3 == 0 && s[i-1] != '-' {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+// FormatBytes returns a human readable size (e.g. 1.5 MB), where each unit
+// is 1024 times the size of the previous.
+func FormatBytes(n int64) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	exp := int(math.Log(float64(n)) / math.Log(1024))
+	if exp >= len(units) {
+		exp = len(units) - 1
+	}
+
+	return fmt.Sprintf("%.1f %s", float64(n)/math.Pow(1024, float64(exp)), units[exp])
+}
+
+// ParseBytes parses a human readable size (e.g. 512MB or 2 GB) into a
+// number of bytes. Numbers without a unit are treated as bytes.
+func ParseBytes(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i == -1 {
+		i = len(s)
+	}
+
+	n, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing size %q: %w", s, err)
+	}
+
+	unit := strings.TrimSpace(s[i:])
+	if unit == "" {
+		unit = "B"
+	}
+
+	for exp, u := range units {
+		if unit == u || unit == strings.TrimSuffix(u, "B") || unit == strings.TrimSuffix(u, "B")+"IB" {
+			return toBytes(n * math.Pow(1024, float64(exp))), nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid size unit %q", unit)
+}
@@ -0,0 +1,103 @@
+package explain
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatCount(t *testing.T) {
+	cases := []struct {
+		n   int
+		exp string
+	}{
+		{n: 0, exp: "0"},
+		{n: 999, exp: "999"},
+		{n: 1000, exp: "1,000"},
+		{n: 1234567, exp: "1,234,567"},
+		{n: -1234, exp: "-1,234"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.exp, func(t *testing.T) {
+			assert.Equal(t, c.exp, FormatCount(c.n))
+		})
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	cases := []struct {
+		n   int64
+		exp string
+	}{
+		{n: 0, exp: "0 B"},
+		{n: 1023, exp: "1023 B"},
+		{n: 1536, exp: "1.5 KB"},
+		{n: 10 << 20, exp: "10.0 MB"},
+		{n: 3 << 30, exp: "3.0 GB"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.exp, func(t *testing.T) {
+			assert.Equal(t, c.exp, FormatBytes(c.n))
+		})
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	cases := []struct {
+		s      string
+		exp    int64
+		expErr string
+	}{
+		{s: "100", exp: 100},
+		{s: "0", exp: 0},
+		{s: "512KB", exp: 512 << 10},
+		{s: "1.5 mb", exp: 3 << 19},
+		{s: "2G", exp: 2 << 30},
+		{s: "2GiB", exp: 2 << 30},
+		{s: "1TB", exp: 1 << 40},
+		{s: "2XB", expErr: `invalid size unit "XB"`},
+		{s: "GB", expErr: `parsing size "GB": strconv.ParseFloat: parsing "": invalid syntax`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.s, func(t *testing.T) {
+			act, err := ParseBytes(c.s)
+			if c.expErr != "" {
+				assert.EqualError(t, err, c.expErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, c.exp, act)
+		})
+	}
+}
+
+func TestPlanWrite(t *testing.T) {
+	p := Plan{
+		Tables: []Table{
+			{Name: "person", Rows: 1000, Columns: 2, Memory: 40000, Output: 12000},
+			{Name: "person_event", Rows: 2000000, Columns: 2, Memory: 3 << 20, Output: 1 << 20, Notes: []string{"each: person (1,000) × event (2,000)"}},
+			{Name: "tmp", Rows: 10, Columns: 1, Memory: 200, Suppressed: true},
+		},
+		Memory:   40000 + 3<<20 + 200,
+		Output:   12000 + 1<<20,
+		Warnings: []string{`table "person_event" will generate 2,000,000 rows (threshold 1,000,000)`},
+	}
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, p.Write(buf))
+
+	exp := `#  TABLE         ROWS       COLUMNS  MEMORY   OUTPUT   NOTES
+1  person        1,000      2        39.1 KB  11.7 KB  
+2  person_event  2,000,000  2        3.0 MB   1.0 MB   each: person (1,000) × event (2,000)
+3  tmp           10         1        200 B    -        
+   total                             3.0 MB   1.0 MB   
+
+warning: table "person_event" will generate 2,000,000 rows (threshold 1,000,000)
+`
+	assert.Equal(t, exp, buf.String())
+}
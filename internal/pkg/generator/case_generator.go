@@ -0,0 +1,188 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/samber/lo"
+)
+
+// ColumnGenerator generates the values of a single column, and allows
+// generators to embed other generators.
+type ColumnGenerator func(t model.Table, c model.Column, files map[string]model.CSVFile) error
+
+// CaseGenerator provides additional context to a case column.
+type CaseGenerator struct {
+	When []CaseBranch `yaml:"when,omitempty"`
+	Else *CaseBranch  `yaml:"else,omitempty"`
+}
+
+// CaseBranch generates the values of a case column for the rows that match
+// all of its conditions, using any other column type's processor.
+type CaseBranch struct {
+	Column  string   `yaml:"column,omitempty"`
+	Equals  *string  `yaml:"equals,omitempty"`
+	In      []string `yaml:"in,omitempty,flow"`
+	Matches string   `yaml:"matches,omitempty"`
+	GT      *float64 `yaml:"gt,omitempty"`
+	GTE     *float64 `yaml:"gte,omitempty"`
+	LT      *float64 `yaml:"lt,omitempty"`
+	LTE     *float64 `yaml:"lte,omitempty"`
+
+	Type      string           `yaml:"type,omitempty"`
+	Processor model.RawMessage `yaml:"processor,omitempty"`
+
+	matches *regexp.Regexp
+}
+
+// Generate values for a column by taking each row's value from the first
+// branch whose conditions it matches. Rows that don't match any branch take
+// the value of the else branch, or are left empty if there isn't one.
+func (g CaseGenerator) Generate(t model.Table, c model.Column, files map[string]model.CSVFile, generate ColumnGenerator) error {
+	if len(g.When) == 0 {
+		return fmt.Errorf("case must have at least one 'when' branch")
+	}
+
+	file := files[t.Name]
+
+	count := file.RowCount()
+	if count == 0 {
+		count = t.Count
+	}
+
+	columns := make([][]string, len(g.When))
+	for i, b := range g.When {
+		if b.Column == "" {
+			return fmt.Errorf("branch %d must have a 'column'", i+1)
+		}
+
+		colIndex := lo.IndexOf(file.Header, b.Column)
+		if colIndex == -1 {
+			return fmt.Errorf("column %q must be generated before case column %q", b.Column, c.Name)
+		}
+		columns[i] = file.Lines[colIndex]
+
+		if b.Matches != "" {
+			var err error
+			if g.When[i].matches, err = regexp.Compile(b.Matches); err != nil {
+				return fmt.Errorf("parsing matches for branch %d: %w", i+1, err)
+			}
+		}
+	}
+
+	branches := g.When
+	if g.Else != nil {
+		branches = append(branches[:len(branches):len(branches)], *g.Else)
+	}
+
+	// Find the rows that take each branch.
+	rows := make([][]int, len(branches))
+	for row := 0; row < count; row++ {
+		branch := len(g.When)
+		for i, b := range g.When {
+			if b.match(valueAt(columns[i], row)) {
+				branch = i
+				break
+			}
+		}
+
+		if branch < len(branches) {
+			rows[branch] = append(rows[branch], row)
+		}
+	}
+
+	line := make([]string, count)
+	for i, b := range branches {
+		if b.Type == "" {
+			return fmt.Errorf("branch %d must have a 'type'", i+1)
+		}
+
+		if len(rows[i]) == 0 {
+			continue
+		}
+
+		values, err := b.generate(t, c, files, rows[i], generate)
+		if err != nil {
+			return fmt.Errorf("generating branch %d: %w", i+1, err)
+		}
+
+		for j, row := range rows[i] {
+			line[row] = valueAt(values, j)
+		}
+	}
+
+	AddTable(t, c.Name, line, files)
+	return nil
+}
+
+// generate runs a branch's processor for the rows that take the branch,
+// using a copy of the table that only contains those rows, so that
+// processors can refer to the table's columns, and options that apply to a
+// whole column (e.g. a ref's coverage) apply to the branch's rows.
+func (b CaseBranch) generate(t model.Table, c model.Column, files map[string]model.CSVFile, rows []int, generate ColumnGenerator) ([]string, error) {
+	file := files[t.Name]
+
+	branchFile := file
+	branchFile.Header = append([]string{}, file.Header...)
+	branchFile.Lines = lo.Map(file.Lines, func(line []string, _ int) []string {
+		return lo.Map(rows, func(row, _ int) string {
+			return valueAt(line, row)
+		})
+	})
+
+	branchFiles := lo.Assign(files)
+	branchFiles[t.Name] = branchFile
+	t.Count = len(rows)
+
+	col := model.Column{Name: c.Name, Type: b.Type, Generator: b.Processor}
+	if err := generate(t, col, branchFiles); err != nil {
+		return nil, err
+	}
+
+	branchFile = branchFiles[t.Name]
+	if len(branchFile.Header) == len(file.Header) {
+		return nil, fmt.Errorf("%q is not a valid column type", b.Type)
+	}
+
+	// Take the first column generated, as some processors (e.g. ref)
+	// generate additional columns.
+	return branchFile.Lines[len(file.Header)], nil
+}
+
+// match returns true if a value matches all of a branch's conditions.
+func (b CaseBranch) match(v string) bool {
+	if b.Equals != nil && v != *b.Equals {
+		return false
+	}
+
+	if b.In != nil && !lo.Contains(b.In, v) {
+		return false
+	}
+
+	if b.matches != nil && !b.matches.MatchString(v) {
+		return false
+	}
+
+	if b.GT == nil && b.GTE == nil && b.LT == nil && b.LTE == nil {
+		return true
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return false
+	}
+
+	return (b.GT == nil || f > *b.GT) &&
+		(b.GTE == nil || f >= *b.GTE) &&
+		(b.LT == nil || f < *b.LT) &&
+		(b.LTE == nil || f <= *b.LTE)
+}
+
+func valueAt(values []string, i int) string {
+	if i < len(values) {
+		return values[i]
+	}
+	return ""
+}
@@ -0,0 +1,251 @@
+package generator
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateCaseColumn(t *testing.T) {
+	set := func(values ...string) model.RawMessage {
+		return model.ToRawMessage(t, SetGenerator{Values: values})
+	}
+
+	cases := []struct {
+		name      string
+		values    []string
+		generator CaseGenerator
+		exp       []string
+		expErr    string
+	}{
+		{
+			name:   "equals",
+			values: []string{"a", "b", "c"},
+			generator: CaseGenerator{
+				When: []CaseBranch{
+					{Column: "input", Equals: lo.ToPtr("b"), Type: "set", Processor: set("x")},
+				},
+			},
+			exp: []string{"", "x", ""},
+		},
+		{
+			name:   "in",
+			values: []string{"a", "b", "c"},
+			generator: CaseGenerator{
+				When: []CaseBranch{
+					{Column: "input", In: []string{"a", "c"}, Type: "set", Processor: set("x")},
+				},
+			},
+			exp: []string{"x", "", "x"},
+		},
+		{
+			name:   "matches",
+			values: []string{"abc", "bcd", "cde"},
+			generator: CaseGenerator{
+				When: []CaseBranch{
+					{Column: "input", Matches: "^b", Type: "set", Processor: set("x")},
+				},
+			},
+			exp: []string{"", "x", ""},
+		},
+		{
+			name:   "numeric comparisons",
+			values: []string{"1", "5", "10", "abc"},
+			generator: CaseGenerator{
+				When: []CaseBranch{
+					{Column: "input", GTE: lo.ToPtr(5.0), LT: lo.ToPtr(10.0), Type: "set", Processor: set("mid")},
+					{Column: "input", GTE: lo.ToPtr(10.0), Type: "set", Processor: set("high")},
+					{Column: "input", LTE: lo.ToPtr(1.0), Type: "set", Processor: set("low")},
+				},
+			},
+			exp: []string{"low", "mid", "high", ""},
+		},
+		{
+			name:   "first matching branch wins",
+			values: []string{"a", "b"},
+			generator: CaseGenerator{
+				When: []CaseBranch{
+					{Column: "input", Equals: lo.ToPtr("a"), Type: "set", Processor: set("first")},
+					{Column: "input", In: []string{"a", "b"}, Type: "set", Processor: set("second")},
+				},
+			},
+			exp: []string{"first", "second"},
+		},
+		{
+			name:   "else",
+			values: []string{"a", "b", "c"},
+			generator: CaseGenerator{
+				When: []CaseBranch{
+					{Column: "input", Equals: lo.ToPtr("a"), Type: "set", Processor: set("x")},
+				},
+				Else: &CaseBranch{Type: "set", Processor: set("y")},
+			},
+			exp: []string{"x", "y", "y"},
+		},
+		{
+			name:      "no branches",
+			values:    []string{"a"},
+			generator: CaseGenerator{},
+			expErr:    "case must have at least one 'when' branch",
+		},
+		{
+			name:   "missing column",
+			values: []string{"a"},
+			generator: CaseGenerator{
+				When: []CaseBranch{
+					{Column: "abc", Equals: lo.ToPtr("a"), Type: "set", Processor: set("x")},
+				},
+			},
+			expErr: `column "abc" must be generated before case column "output"`,
+		},
+		{
+			name:   "invalid matches",
+			values: []string{"a"},
+			generator: CaseGenerator{
+				When: []CaseBranch{
+					{Column: "input", Matches: "[", Type: "set", Processor: set("x")},
+				},
+			},
+			expErr: "parsing matches for branch 1: error parsing regexp: missing closing ]: `[`",
+		},
+		{
+			name:   "missing type",
+			values: []string{"a"},
+			generator: CaseGenerator{
+				When: []CaseBranch{
+					{Column: "input", Equals: lo.ToPtr("a"), Processor: set("x")},
+				},
+			},
+			expErr: "branch 1 must have a 'type'",
+		},
+		{
+			name:   "invalid type",
+			values: []string{"a"},
+			generator: CaseGenerator{
+				When: []CaseBranch{
+					{Column: "input", Equals: lo.ToPtr("a"), Type: "abc", Processor: set("x")},
+				},
+			},
+			expErr: `generating branch 1: "abc" is not a valid column type`,
+		},
+	}
+
+	// A cut-down version of the column generation that dg performs.
+	generate := func(t model.Table, c model.Column, files map[string]model.CSVFile) error {
+		if c.Type != "set" {
+			return nil
+		}
+
+		var g SetGenerator
+		if err := c.Generator.UnmarshalFunc(&g); err != nil {
+			return err
+		}
+		return g.Generate(t, c, files)
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			table := model.Table{
+				Name:  "table",
+				Count: len(c.values),
+			}
+
+			files := map[string]model.CSVFile{
+				"table": {
+					Name:   "table",
+					Header: []string{"input"},
+					Lines:  [][]string{c.values},
+				},
+			}
+
+			column := model.Column{Name: "output"}
+
+			err := c.generator.Generate(table, column, files, generate)
+			if c.expErr != "" {
+				assert.Equal(t, c.expErr, err.Error())
+				return
+			}
+			assert.Nil(t, err)
+
+			file := files["table"]
+			assert.Equal(t, []string{"input", "output"}, file.Header)
+			assert.Equal(t, c.exp, file.Lines[1])
+		})
+	}
+}
+
+func TestGenerateCaseColumnBranchRows(t *testing.T) {
+	parents := lo.Map(lo.Range(10), func(i, _ int) string { return strconv.Itoa(i) })
+
+	ref := func(g RefGenerator) model.RawMessage {
+		g.Table, g.Column = "parent", "id"
+		return model.ToRawMessage(t, g)
+	}
+
+	cases := []struct {
+		name     string
+		branch   CaseBranch
+		expShape func(t *testing.T, values []string)
+	}{
+		{
+			name:   "coverage",
+			branch: CaseBranch{Type: "ref", Processor: ref(RefGenerator{Coverage: "all"})},
+			expShape: func(t *testing.T, values []string) {
+				assert.ElementsMatch(t, parents, lo.Uniq(values))
+			},
+		},
+		{
+			name:   "distinct",
+			branch: CaseBranch{Type: "ref", Processor: ref(RefGenerator{Distinct: true})},
+			expShape: func(t *testing.T, values []string) {
+				assert.ElementsMatch(t, parents, values)
+			},
+		},
+		{
+			name:   "max per parent",
+			branch: CaseBranch{Type: "ref", Processor: ref(RefGenerator{MinPerParent: 1, MaxPerParent: 1})},
+			expShape: func(t *testing.T, values []string) {
+				assert.ElementsMatch(t, parents, values)
+			},
+		},
+	}
+
+	generate := func(t model.Table, c model.Column, files map[string]model.CSVFile) error {
+		var g RefGenerator
+		if err := c.Generator.UnmarshalFunc(&g); err != nil {
+			return err
+		}
+		return g.Generate(t, c, files)
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// Only 10 of the 40 rows take the branch.
+			input := lo.Map(lo.Range(40), func(i, _ int) string {
+				return lo.Ternary(i%4 == 0, "a", "b")
+			})
+
+			files := map[string]model.CSVFile{
+				"parent": {Header: []string{"id"}, Lines: [][]string{parents}},
+				"child":  {Header: []string{"input"}, Lines: [][]string{input}},
+			}
+
+			c.branch.Column = "input"
+			c.branch.Equals = lo.ToPtr("a")
+			g := CaseGenerator{When: []CaseBranch{c.branch}}
+
+			err := g.Generate(model.Table{Name: "child", Count: 40}, model.Column{Name: "parent_id"}, files, generate)
+			assert.Nil(t, err)
+
+			output := files["child"].Lines[1]
+			matched := lo.Filter(output, func(_ string, i int) bool { return input[i] == "a" })
+			unmatched := lo.Filter(output, func(_ string, i int) bool { return input[i] != "a" })
+
+			c.expShape(t, matched)
+			assert.Equal(t, make([]string, 30), unmatched)
+		})
+	}
+}
@@ -10,7 +10,7 @@ import (
 
 // ConstGenerator provides additional context to a const column.
 type ConstGenerator struct {
-	Values []string `yaml:"values"`
+	Values []string `yaml:"values,omitempty,flow"`
 }
 
 // Generate values for a column based on a series of provided values.
@@ -0,0 +1,168 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/codingconcepts/dg/internal/pkg/random"
+)
+
+// DistGenerator provides additional context to a dist column.
+type DistGenerator struct {
+	Type      string   `yaml:"type,omitempty"`
+	Mean      float64  `yaml:"mean,omitempty"`
+	StdDev    float64  `yaml:"stddev,omitempty"`
+	Rate      float64  `yaml:"rate,omitempty"`
+	Lambda    float64  `yaml:"lambda,omitempty"`
+	S         float64  `yaml:"s,omitempty"`
+	V         float64  `yaml:"v,omitempty"`
+	Alpha     float64  `yaml:"alpha,omitempty"`
+	Beta      float64  `yaml:"beta,omitempty"`
+	Min       *float64 `yaml:"min,omitempty"`
+	Max       *float64 `yaml:"max,omitempty"`
+	Precision *int     `yaml:"precision,omitempty"`
+	Format    string   `yaml:"format,omitempty"`
+}
+
+func (g DistGenerator) GetFormat() string {
+	return g.Format
+}
+
+// Generate values for a column by sampling from a statistical distribution.
+func (g DistGenerator) Generate(t model.Table, c model.Column, files map[string]model.CSVFile) error {
+	sample, err := g.sampler()
+	if err != nil {
+		return err
+	}
+
+	count := files[t.Name].RowCount()
+	if count == 0 {
+		count = t.Count
+	}
+
+	line := make([]string, count)
+	for i := 0; i < count; i++ {
+		line[i] = g.format(g.clamp(sample()))
+	}
+
+	AddTable(t, c.Name, line, files)
+	return nil
+}
+
+// sampler validates the generator's parameters and returns a function that
+// samples from its distribution.
+func (g DistGenerator) sampler() (func() float64, error) {
+	if g.Precision != nil && *g.Precision < 0 {
+		return nil, fmt.Errorf("precision must be 0 or greater")
+	}
+
+	switch g.Type {
+	case "normal":
+		return func() float64 {
+			return g.Mean + g.StdDev*random.NormFloat64()
+		}, nil
+
+	case "lognormal":
+		return func() float64 {
+			return math.Exp(g.Mean + g.StdDev*random.NormFloat64())
+		}, nil
+
+	case "exponential":
+		if g.Rate <= 0 {
+			return nil, fmt.Errorf("exponential distribution requires a 'rate' greater than 0")
+		}
+		return func() float64 {
+			return random.ExpFloat64() / g.Rate
+		}, nil
+
+	case "poisson":
+		if g.Lambda <= 0 {
+			return nil, fmt.Errorf("poisson distribution requires a 'lambda' greater than 0")
+		}
+		return func() float64 {
+			return float64(random.Poisson(g.Lambda))
+		}, nil
+
+	case "zipf":
+		if g.S <= 1 {
+			return nil, fmt.Errorf("zipf distribution requires an 's' greater than 1")
+		}
+		if g.Max == nil {
+			return nil, fmt.Errorf("zipf distribution requires a 'max'")
+		}
+
+		min := g.min(0)
+		if *g.Max < min {
+			return nil, fmt.Errorf("zipf distribution requires a 'max' greater than its 'min'")
+		}
+
+		zipf := random.NewZipf(g.S, math.Max(g.V, 1), uint64(*g.Max-min))
+		return func() float64 {
+			return min + float64(zipf.Uint64())
+		}, nil
+
+	case "beta":
+		if g.Alpha <= 0 || g.Beta <= 0 {
+			return nil, fmt.Errorf("beta distribution requires an 'alpha' and 'beta' greater than 0")
+		}
+
+		min := g.min(0)
+		max := 1.0
+		if g.Max != nil {
+			max = *g.Max
+		}
+		return func() float64 {
+			return min + (max-min)*random.Beta(g.Alpha, g.Beta)
+		}, nil
+
+	case "uniform":
+		if g.Min == nil || g.Max == nil || *g.Min > *g.Max {
+			return nil, fmt.Errorf("uniform distribution requires a 'min' and a 'max' greater than or equal to it")
+		}
+		return func() float64 {
+			return *g.Min + (*g.Max-*g.Min)*random.Float64()
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("%q is not a valid distribution type", g.Type)
+	}
+}
+
+func (g DistGenerator) min(def float64) float64 {
+	if g.Min == nil {
+		return def
+	}
+	return *g.Min
+}
+
+func (g DistGenerator) clamp(v float64) float64 {
+	if g.Min != nil && v < *g.Min {
+		v = *g.Min
+	}
+	if g.Max != nil && v > *g.Max {
+		v = *g.Max
+	}
+
+	return v
+}
+
+func (g DistGenerator) format(v float64) string {
+	precision := -1
+	if g.Precision != nil {
+		precision = *g.Precision
+		pow := math.Pow(10, float64(precision))
+		v = math.Round(v*pow) / pow
+	}
+
+	if g.Format == "" {
+		return strconv.FormatFloat(v, 'f', precision, 64)
+	}
+
+	// Allow whole numbers to be formatted as integers (e.g. "%05d").
+	if precision == 0 {
+		return formatValue(g, int64(v))
+	}
+	return formatValue(g, v)
+}
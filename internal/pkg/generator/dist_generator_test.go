@@ -0,0 +1,145 @@
+package generator
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateDistColumn(t *testing.T) {
+	cases := []struct {
+		name      string
+		generator DistGenerator
+		expShape  func(t *testing.T, values []string)
+		expErr    string
+	}{
+		{
+			name:      "normal with precision",
+			generator: DistGenerator{Type: "normal", Mean: 100, StdDev: 10, Precision: lo.ToPtr(2)},
+			expShape: func(t *testing.T, values []string) {
+				assertMeanNear(t, values, 100, 2)
+				for _, v := range values {
+					assert.Regexp(t, `^\d+(\.\d{1,2})?$`, v)
+				}
+			},
+		},
+		{
+			name:      "lognormal clamped",
+			generator: DistGenerator{Type: "lognormal", Mean: 3, StdDev: 1, Max: lo.ToPtr(50.0)},
+			expShape: func(t *testing.T, values []string) {
+				assertBetween(t, values, 0, 50)
+			},
+		},
+		{
+			name:      "exponential",
+			generator: DistGenerator{Type: "exponential", Rate: 0.5},
+			expShape: func(t *testing.T, values []string) {
+				assertMeanNear(t, values, 2, 0.2)
+			},
+		},
+		{
+			name:      "poisson",
+			generator: DistGenerator{Type: "poisson", Lambda: 4},
+			expShape: func(t *testing.T, values []string) {
+				assertMeanNear(t, values, 4, 0.3)
+				for _, v := range values {
+					assert.Regexp(t, `^\d+$`, v)
+				}
+			},
+		},
+		{
+			name:      "zipf",
+			generator: DistGenerator{Type: "zipf", S: 2, Min: lo.ToPtr(1.0), Max: lo.ToPtr(10.0)},
+			expShape: func(t *testing.T, values []string) {
+				assertBetween(t, values, 1, 10)
+
+				// The lowest values are the most common.
+				counts := lo.CountValues(values)
+				assert.Greater(t, counts["1"], counts["2"])
+				assert.Greater(t, counts["2"], counts["3"])
+			},
+		},
+		{
+			name:      "beta scaled",
+			generator: DistGenerator{Type: "beta", Alpha: 2, Beta: 2, Min: lo.ToPtr(10.0), Max: lo.ToPtr(20.0)},
+			expShape: func(t *testing.T, values []string) {
+				assertBetween(t, values, 10, 20)
+				assertMeanNear(t, values, 15, 0.5)
+			},
+		},
+		{
+			name:      "uniform formatted",
+			generator: DistGenerator{Type: "uniform", Min: lo.ToPtr(1.0), Max: lo.ToPtr(5.0), Precision: lo.ToPtr(0), Format: "%03d"},
+			expShape: func(t *testing.T, values []string) {
+				for _, v := range values {
+					assert.Contains(t, []string{"001", "002", "003", "004", "005"}, v)
+				}
+			},
+		},
+		{
+			name:      "invalid type",
+			generator: DistGenerator{Type: "gaussian"},
+			expErr:    `"gaussian" is not a valid distribution type`,
+		},
+		{
+			name:      "missing rate",
+			generator: DistGenerator{Type: "exponential"},
+			expErr:    "exponential distribution requires a 'rate' greater than 0",
+		},
+		{
+			name:      "zipf without max",
+			generator: DistGenerator{Type: "zipf", S: 2},
+			expErr:    "zipf distribution requires a 'max'",
+		},
+		{
+			name:      "uniform without bounds",
+			generator: DistGenerator{Type: "uniform", Min: lo.ToPtr(1.0)},
+			expErr:    "uniform distribution requires a 'min' and a 'max' greater than or equal to it",
+		},
+		{
+			name:      "negative precision",
+			generator: DistGenerator{Type: "normal", Precision: lo.ToPtr(-1)},
+			expErr:    "precision must be 0 or greater",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			table := model.Table{Name: "table", Count: 5000}
+			files := map[string]model.CSVFile{}
+
+			err := c.generator.Generate(table, model.Column{Name: "col"}, files)
+			if c.expErr != "" {
+				assert.EqualError(t, err, c.expErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, files["table"].Lines[0], table.Count)
+			c.expShape(t, files["table"].Lines[0])
+		})
+	}
+}
+
+func assertMeanNear(t *testing.T, values []string, exp, delta float64) {
+	var total float64
+	for _, v := range values {
+		f, err := strconv.ParseFloat(v, 64)
+		assert.NoError(t, err)
+		total += f
+	}
+
+	assert.InDelta(t, exp, total/float64(len(values)), delta)
+}
+
+func assertBetween(t *testing.T, values []string, min, max float64) {
+	for _, v := range values {
+		f, err := strconv.ParseFloat(v, 64)
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, f, min)
+		assert.LessOrEqual(t, f, max)
+	}
+}
@@ -2,16 +2,22 @@ package generator
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/codingconcepts/dg/internal/pkg/random"
 
 	"github.com/samber/lo"
 )
 
 // EachGenerator provides additional context to an each or ref column.
 type EachGenerator struct {
-	Table  string `yaml:"table"`
-	Column string `yaml:"column"`
+	Table  string `yaml:"table,omitempty"`
+	Column string `yaml:"column,omitempty"`
+
+	// PerRow is the number of rows to generate for each source row, which
+	// is either between a min and max, or sampled from a distribution.
+	PerRow *DistGenerator `yaml:"per_row,omitempty"`
 }
 
 // Generate looks for any each type columns for a table, and
@@ -26,6 +32,7 @@ func (g EachGenerator) Generate(t model.Table, files map[string]model.CSVFile) e
 	}
 
 	var preCartesian [][]string
+	var fanOuts []func() int
 	for _, col := range cols {
 		var gCol EachGenerator
 		if err := col.Generator.UnmarshalFunc(&gCol); err != nil {
@@ -41,15 +48,70 @@ func (g EachGenerator) Generate(t model.Table, files map[string]model.CSVFile) e
 		}
 
 		preCartesian = append(preCartesian, srcTable.Lines[srcColumnIndex])
+
+		if gCol.PerRow != nil {
+			fanOut, err := gCol.PerRow.FanOut()
+			if err != nil {
+				return fmt.Errorf("parsing per_row for %s.%s: %w", t.Name, col.Name, err)
+			}
+			fanOuts = append(fanOuts, fanOut)
+		}
 	}
 
 	// Compute Cartesian product of all columns.
-	cartesianColumns := Transpose(CartesianProduct(preCartesian...))
+	rows := CartesianProduct(preCartesian...)
 
-	// Add the header
-	for i, col := range cartesianColumns {
-		AddTable(t, cols[i].Name, col, files)
+	// Repeat each combination of source rows by the product of their fan-outs.
+	if len(fanOuts) > 0 {
+		var repeated [][]string
+		for _, row := range rows {
+			n := 1
+			for _, fanOut := range fanOuts {
+				n *= fanOut()
+			}
+
+			for i := 0; i < n; i++ {
+				repeated = append(repeated, row)
+			}
+		}
+		rows = repeated
+	}
+
+	cartesianColumns := Transpose(rows)
+
+	// Add the header (even if a fan-out of zero resulted in no rows).
+	for i, col := range cols {
+		var line []string
+		if i < len(cartesianColumns) {
+			line = cartesianColumns[i]
+		}
+		AddTable(t, col.Name, line, files)
 	}
 
 	return nil
 }
+
+// FanOut returns a function that samples the number of rows to generate for
+// a source row. Without a distribution type, numbers are evenly spread
+// between min and max (inclusive).
+func (g DistGenerator) FanOut() (func() int, error) {
+	if g.Type == "" {
+		if g.Min == nil || g.Max == nil || *g.Min < 0 || *g.Min > *g.Max {
+			return nil, fmt.Errorf("per_row requires either a distribution 'type', or a 'min' of 0 or greater and a 'max' greater than or equal to it")
+		}
+
+		min, max := int(*g.Min), int(*g.Max)
+		return func() int {
+			return min + random.Intn(max-min+1)
+		}, nil
+	}
+
+	sample, err := g.sampler()
+	if err != nil {
+		return nil, err
+	}
+
+	return func() int {
+		return int(math.Max(0, math.Round(g.clamp(sample()))))
+	}, nil
+}
@@ -1,9 +1,11 @@
 package generator
 
 import (
+	"strconv"
 	"testing"
 
 	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -65,3 +67,105 @@ func TestGenerateEachColumn(t *testing.T) {
 	}
 	assert.Equal(t, exp, files["person_event"])
 }
+
+func TestGenerateEachColumnPerRow(t *testing.T) {
+	cases := []struct {
+		name   string
+		perRow *DistGenerator
+		expMin int
+		expMax int
+		expErr string
+	}{
+		{
+			name:   "min and max",
+			perRow: &DistGenerator{Min: lo.ToPtr(1.0), Max: lo.ToPtr(5.0)},
+			expMin: 1,
+			expMax: 5,
+		},
+		{
+			name:   "fixed",
+			perRow: &DistGenerator{Min: lo.ToPtr(3.0), Max: lo.ToPtr(3.0)},
+			expMin: 3,
+			expMax: 3,
+		},
+		{
+			name:   "distribution",
+			perRow: &DistGenerator{Type: "poisson", Lambda: 2, Min: lo.ToPtr(1.0), Max: lo.ToPtr(4.0)},
+			expMin: 1,
+			expMax: 4,
+		},
+		{
+			name:   "missing max",
+			perRow: &DistGenerator{Min: lo.ToPtr(1.0)},
+			expErr: "parsing per_row for line_item.order_id: per_row requires either a distribution 'type', or a 'min' of 0 or greater and a 'max' greater than or equal to it",
+		},
+		{
+			name:   "invalid distribution",
+			perRow: &DistGenerator{Type: "abc"},
+			expErr: `parsing per_row for line_item.order_id: "abc" is not a valid distribution type`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			table := model.Table{
+				Name: "line_item",
+				Columns: []model.Column{
+					{
+						Name: "order_id",
+						Type: "each",
+						Generator: model.ToRawMessage(t, EachGenerator{
+							Table:  "order",
+							Column: "id",
+							PerRow: c.perRow,
+						}),
+					},
+				},
+			}
+
+			orders := lo.Map(lo.Range(100), func(i, _ int) string { return strconv.Itoa(i) })
+			files := map[string]model.CSVFile{
+				"order": {Name: "order", Header: []string{"id"}, Lines: [][]string{orders}},
+			}
+
+			err := EachGenerator{}.Generate(table, files)
+			if c.expErr != "" {
+				assert.Equal(t, c.expErr, err.Error())
+				return
+			}
+			assert.Nil(t, err)
+
+			counts := lo.CountValues(files["line_item"].Lines[0])
+			assert.Len(t, counts, 100)
+			for _, n := range counts {
+				assert.GreaterOrEqual(t, n, c.expMin)
+				assert.LessOrEqual(t, n, c.expMax)
+			}
+		})
+	}
+}
+
+func TestGenerateEachColumnPerRowZero(t *testing.T) {
+	table := model.Table{
+		Name: "line_item",
+		Columns: []model.Column{
+			{
+				Name: "order_id",
+				Type: "each",
+				Generator: model.ToRawMessage(t, EachGenerator{
+					Table:  "order",
+					Column: "id",
+					PerRow: &DistGenerator{Min: lo.ToPtr(0.0), Max: lo.ToPtr(0.0)},
+				}),
+			},
+		},
+	}
+
+	files := map[string]model.CSVFile{
+		"order": {Name: "order", Header: []string{"id"}, Lines: [][]string{{"a", "b"}}},
+	}
+
+	assert.Nil(t, EachGenerator{}.Generate(table, files))
+	assert.Equal(t, []string{"order_id"}, files["line_item"].Header)
+	assert.Empty(t, files["line_item"].Lines[0])
+}
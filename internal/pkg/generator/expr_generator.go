@@ -0,0 +1,161 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/expr-lang/expr"
+	"github.com/samber/lo"
+)
+
+var (
+	intValueRegex   = regexp.MustCompile(`^-?(0|[1-9]\d*)$`)
+	floatValueRegex = regexp.MustCompile(`^-?(0|[1-9]\d*)(\.\d+)?$`)
+)
+
+// ExprGenerator provides additional context to an expr column.
+type ExprGenerator struct {
+	Expression string `yaml:"expression,omitempty"`
+	Format     string `yaml:"format,omitempty"`
+}
+
+func (g ExprGenerator) GetFormat() string {
+	return g.Format
+}
+
+// Generate evaluates an expression for each row of a table, using the values
+// of the columns generated before it.
+func (g ExprGenerator) Generate(t model.Table, c model.Column, files map[string]model.CSVFile) error {
+	if g.Expression == "" {
+		return fmt.Errorf("expr must have an 'expression'")
+	}
+
+	file := files[t.Name]
+
+	count := file.RowCount()
+	if count == 0 {
+		count = t.Count
+	}
+
+	// Columns are typed by their values, so that numeric columns can be used
+	// in arithmetic and boolean columns in conditions.
+	columns := make([][]any, len(file.Header))
+	env := map[string]any{}
+	for i, name := range file.Header {
+		columns[i] = typedValues(file.Lines[i])
+		env[name] = zeroValue(columns[i])
+	}
+
+	program, err := expr.Compile(g.Expression, expr.Env(env))
+	if err != nil {
+		return fmt.Errorf("compiling expression: %w", err)
+	}
+
+	line := make([]string, count)
+	for row := 0; row < count; row++ {
+		nulls := false
+		for i, name := range file.Header {
+			var v any
+			if row < len(columns[i]) {
+				v = columns[i][row]
+			}
+
+			env[name] = v
+			nulls = nulls || v == nil
+		}
+
+		result, err := expr.Run(program, env)
+		if err != nil {
+			// Treat expressions that can't be evaluated because one of
+			// their columns is null, as null.
+			if nulls {
+				continue
+			}
+			return fmt.Errorf("evaluating expression for row %d: %w", row+1, err)
+		}
+
+		line[row] = g.formatResult(result)
+	}
+
+	AddTable(t, c.Name, line, files)
+	return nil
+}
+
+func (g ExprGenerator) formatResult(result any) string {
+	switch v := result.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		if g.Format == "" {
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	case time.Time:
+		if g.Format == "" {
+			return v.Format(time.RFC3339)
+		}
+	}
+
+	return formatValue(g, result)
+}
+
+// typedValues converts a column of strings into ints, floats, or bools if
+// all of its values can be converted. Empty values are treated as nulls.
+func typedValues(values []string) []any {
+	nonEmpty := lo.Filter(values, func(v string, _ int) bool {
+		return v != ""
+	})
+
+	var convert func(string) any
+	switch {
+	case len(nonEmpty) == 0:
+		return lo.Map(values, func(v string, _ int) any { return v })
+	case lo.EveryBy(nonEmpty, intValueRegex.MatchString):
+		convert = func(v string) any {
+			i, _ := strconv.Atoi(v)
+			return i
+		}
+	case lo.EveryBy(nonEmpty, floatValueRegex.MatchString):
+		convert = func(v string) any {
+			f, _ := strconv.ParseFloat(v, 64)
+			return f
+		}
+	case lo.EveryBy(nonEmpty, func(v string) bool { return v == "true" || v == "false" }):
+		convert = func(v string) any { return v == "true" }
+	default:
+		return lo.Map(values, func(v string, _ int) any { return v })
+	}
+
+	return lo.Map(values, func(v string, _ int) any {
+		if v == "" {
+			return nil
+		}
+		return convert(v)
+	})
+}
+
+// zeroValue returns a value of the same type as a column's values, which
+// allows expressions to be type-checked when they're compiled.
+func zeroValue(values []any) any {
+	v, ok := lo.Find(values, func(v any) bool {
+		return v != nil
+	})
+	if !ok {
+		return ""
+	}
+
+	switch v.(type) {
+	case int:
+		return 0
+	case float64:
+		return 0.0
+	case bool:
+		return false
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,133 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateExprColumn(t *testing.T) {
+	file := model.CSVFile{
+		Name:   "order_item",
+		Header: []string{"quantity", "unit_price", "first", "last", "age", "created", "active"},
+		Lines: [][]string{
+			{"2", "3", ""},
+			{"1.50", "10", "2.25"},
+			{"Alice", "Bob", "Carol"},
+			{"Smith", "Jones", "Brown"},
+			{"17", "18", "40"},
+			{"2023-01-01", "2023-06-30", "2023-12-31"},
+			{"true", "false", "true"},
+		},
+	}
+
+	cases := []struct {
+		name       string
+		expression string
+		format     string
+		exp        []string
+		expErr     string
+	}{
+		{
+			name:       "arithmetic",
+			expression: "quantity * unit_price",
+			exp:        []string{"3", "30", ""},
+		},
+		{
+			name:       "formatted arithmetic",
+			expression: "unit_price * 1.2",
+			format:     "%.2f",
+			exp:        []string{"1.80", "12.00", "2.70"},
+		},
+		{
+			name:       "string concatenation",
+			expression: "first + ' ' + upper(last)",
+			exp:        []string{"Alice SMITH", "Bob JONES", "Carol BROWN"},
+		},
+		{
+			name:       "comparison",
+			expression: "age >= 18",
+			exp:        []string{"false", "true", "true"},
+		},
+		{
+			name:       "conditional",
+			expression: "active && age >= 18 ? 'yes' : 'no'",
+			exp:        []string{"no", "no", "yes"},
+		},
+		{
+			name:       "date arithmetic",
+			expression: "date(created) + duration('36h')",
+			format:     "2006-01-02 15:04",
+			exp:        []string{"2023-01-02 12:00", "2023-07-01 12:00", "2024-01-01 12:00"},
+		},
+		{
+			name:       "built-in functions",
+			expression: "len(first) + max(age, 20)",
+			exp:        []string{"25", "23", "45"},
+		},
+		{
+			name:       "unknown column",
+			expression: "price * 2",
+			expErr:     "compiling expression: unknown name price (1:1)\n | price * 2\n | ^",
+		},
+		{
+			name:       "mismatched types",
+			expression: "first * 2",
+			expErr:     "compiling expression: invalid operation: * (mismatched types string and int) (1:7)\n | first * 2\n | ......^",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			files := map[string]model.CSVFile{
+				file.Name: file,
+			}
+
+			g := ExprGenerator{
+				Expression: c.expression,
+				Format:     c.format,
+			}
+
+			err := g.Generate(model.Table{Name: file.Name}, model.Column{Name: "result"}, files)
+			if c.expErr != "" {
+				assert.EqualError(t, err, c.expErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, c.exp, files[file.Name].Lines[len(file.Header)])
+		})
+	}
+}
+
+func TestGenerateExprColumnWithoutColumns(t *testing.T) {
+	files := map[string]model.CSVFile{}
+
+	g := ExprGenerator{Expression: "1 + 2"}
+	table := model.Table{Name: "table", Count: 2}
+
+	assert.NoError(t, g.Generate(table, model.Column{Name: "three"}, files))
+	assert.Equal(t, [][]string{{"3", "3"}}, files["table"].Lines)
+}
+
+func TestTypedValues(t *testing.T) {
+	cases := []struct {
+		name   string
+		values []string
+		exp    []any
+	}{
+		{name: "ints", values: []string{"1", "", "-20"}, exp: []any{1, nil, -20}},
+		{name: "floats", values: []string{"1", "2.5"}, exp: []any{1.0, 2.5}},
+		{name: "bools", values: []string{"true", "false"}, exp: []any{true, false}},
+		{name: "leading zeros", values: []string{"007", "1"}, exp: []any{"007", "1"}},
+		{name: "strings", values: []string{"a", "", "1"}, exp: []any{"a", "", "1"}},
+		{name: "empty", values: []string{"", ""}, exp: []any{"", ""}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.exp, typedValues(c.values))
+		})
+	}
+}
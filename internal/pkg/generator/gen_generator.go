@@ -2,7 +2,7 @@ package generator
 
 import (
 	"fmt"
-	"strings"
+	"regexp"
 
 	"github.com/codingconcepts/dg/internal/pkg/model"
 	"github.com/codingconcepts/dg/internal/pkg/random"
@@ -10,12 +10,20 @@ import (
 	"github.com/samber/lo"
 )
 
+// maxUniqueAttempts is the number of duplicate values that will be generated
+// in a row for a unique column before assuming that there are no more unique
+// values to generate.
+const maxUniqueAttempts = 1000
+
+var placeholderRegex = regexp.MustCompile(`\$\{[^}]+\}`)
+
 // GenGenerator provides additional context to a gen column.
 type GenGenerator struct {
-	Value          string `yaml:"value"`
-	Pattern        string `yaml:"pattern"`
-	NullPercentage int    `yaml:"null_percentage"`
-	Format         string `yaml:"format"`
+	Value          string `yaml:"value,omitempty"`
+	Pattern        string `yaml:"pattern,omitempty"`
+	NullPercentage int    `yaml:"null_percentage,omitempty"`
+	Format         string `yaml:"format,omitempty"`
+	Unique         bool   `yaml:"unique,omitempty"`
 
 	patternGenerator *reggen.Generator
 }
@@ -41,6 +49,17 @@ func (g GenGenerator) Generate(t model.Table, c model.Column, files map[string]m
 		if g.patternGenerator, err = reggen.NewGenerator(g.Pattern); err != nil {
 			return fmt.Errorf("creating regex generator: %w", err)
 		}
+		g.patternGenerator.SetSeed(random.Int63())
+	}
+
+	if g.Unique {
+		line, err := g.generateUnique(t.Count, c.Transform)
+		if err != nil {
+			return err
+		}
+
+		AddTable(t, c.Name, line, files)
+		return nil
 	}
 
 	var line []string
@@ -53,6 +72,48 @@ func (g GenGenerator) Generate(t model.Table, c model.Column, files map[string]m
 	return nil
 }
 
+// generateUnique generates values that haven't been generated before,
+// retrying each value until a new one is generated. Values are compared after
+// the column's transform (if any) is applied, so that transforms can't
+// reintroduce duplicates. Nulls aren't considered duplicates of one another.
+func (g GenGenerator) generateUnique(count int, tr *model.Transform) ([]string, error) {
+	seen := make(map[string]struct{}, count)
+	line := make([]string, count)
+
+	for i := range line {
+		for attempt := 0; ; attempt++ {
+			if attempt == maxUniqueAttempts {
+				return nil, fmt.Errorf(
+					"unable to generate a unique value for row %d of %d after %d attempts, as only %d unique values could be generated; use a %s with more possible values",
+					i+1, count, maxUniqueAttempts, len(seen), lo.Ternary(g.Pattern != "", "pattern", "value"))
+			}
+
+			s := g.generate()
+
+			key := s
+			if tr != nil {
+				var err error
+				if key, err = transformedValue(*tr, s); err != nil {
+					return nil, fmt.Errorf("transforming row %d: %w", i+1, err)
+				}
+			}
+
+			if key == "" {
+				line[i] = s
+				break
+			}
+
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				line[i] = s
+				break
+			}
+		}
+	}
+
+	return line, nil
+}
+
 func (pg GenGenerator) generate() string {
 	r := random.Intn(100)
 	if r < pg.NullPercentage {
@@ -70,13 +131,22 @@ func (pg GenGenerator) generate() string {
 		return formatValue(pg, v())
 	}
 
-	// Process multipe-replacements.
-	for k, v := range replacements {
-		if strings.Contains(s, k) {
-			valueStr := formatValue(pg, v())
-			s = strings.ReplaceAll(s, k, valueStr)
+	// Process multiple-replacements from left to right, so that values are
+	// generated in the same order each time. Repeated placeholders share a
+	// value.
+	values := map[string]string{}
+	return placeholderRegex.ReplaceAllStringFunc(s, func(k string) string {
+		if valueStr, ok := values[k]; ok {
+			return valueStr
+		}
+
+		v, ok := replacements[k]
+		if !ok {
+			return k
 		}
-	}
 
-	return s
+		valueStr := formatValue(pg, v())
+		values[k] = valueStr
+		return valueStr
+	})
 }
@@ -7,8 +7,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/brianvoe/gofakeit/v6"
 	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/codingconcepts/dg/internal/pkg/random"
 	"github.com/lucasjones/reggen"
+	"github.com/samber/lo"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -97,3 +100,103 @@ func BenchmarkGeneratePattern(b *testing.B) {
 		g.generate()
 	}
 }
+
+func TestGenerateGenColumnUnique(t *testing.T) {
+	cases := []struct {
+		name      string
+		generator GenGenerator
+		transform *model.Transform
+		count     int
+		expErr    string
+	}{
+		{
+			name:      "pattern",
+			generator: GenGenerator{Pattern: `\d{3}`, Unique: true},
+			count:     900,
+		},
+		{
+			name:      "value",
+			generator: GenGenerator{Value: "${uint8}", Unique: true},
+			count:     200,
+		},
+		{
+			name:      "with nulls",
+			generator: GenGenerator{Pattern: `[a-j]`, NullPercentage: 50, Unique: true},
+			count:     10,
+		},
+		{
+			name:      "exhausted",
+			generator: GenGenerator{Pattern: `[a-j]`, Unique: true},
+			count:     11,
+			expErr:    "unable to generate a unique value for row 11 of 11 after 1000 attempts, as only 10 unique values could be generated; use a pattern with more possible values",
+		},
+		{
+			name:      "transformed",
+			generator: GenGenerator{Pattern: `[a-z]{6}`, Unique: true},
+			transform: &model.Transform{MaxLength: 1},
+			count:     26,
+		},
+		{
+			name:      "transformed with default",
+			generator: GenGenerator{Pattern: `[a-j]`, NullPercentage: 50, Unique: true},
+			transform: &model.Transform{Default: "z"},
+			count:     11,
+		},
+		{
+			name:      "transformed exhausted",
+			generator: GenGenerator{Pattern: `[a-z]{6}`, Unique: true},
+			transform: &model.Transform{Case: "lower", MaxLength: 1},
+			count:     27,
+			expErr:    "unable to generate a unique value for row 27 of 27 after 1000 attempts, as only 26 unique values could be generated; use a pattern with more possible values",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			table := model.Table{Name: "table", Count: c.count}
+			files := map[string]model.CSVFile{}
+
+			column := model.Column{Name: "col", Transform: c.transform}
+
+			err := c.generator.Generate(table, column, files)
+			if c.expErr != "" {
+				assert.Equal(t, c.expErr, err.Error())
+				return
+			}
+			assert.Nil(t, err)
+			assert.Nil(t, Transform(table, column, files))
+
+			values := files["table"].Lines[0]
+			assert.Len(t, values, c.count)
+
+			nonNull := lo.Filter(values, func(v string, _ int) bool { return v != "" })
+			assert.Equal(t, len(nonNull), len(lo.Uniq(nonNull)))
+		})
+	}
+}
+
+func TestGenerateGenColumnSeeded(t *testing.T) {
+	generate := func() []string {
+		random.Seed(7)
+		gofakeit.Seed(7)
+
+		g := GenGenerator{Value: "${first_name} ${last_name} <${email}> ${first_name}"}
+		files := map[string]model.CSVFile{}
+
+		err := g.Generate(model.Table{Name: "person", Count: 20}, model.Column{Name: "name"}, files)
+		assert.Nil(t, err)
+
+		return files["person"].Lines[0]
+	}
+
+	exp := generate()
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, exp, generate())
+	}
+
+	// Repeated placeholders share a value.
+	for _, v := range exp {
+		fields := strings.Fields(v)
+		assert.Equal(t, fields[0], fields[len(fields)-1])
+	}
+}
@@ -1,14 +1,22 @@
 package generator
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/codingconcepts/dg/internal/pkg/model"
 	"github.com/samber/lo"
 )
 
 // IncGenerator provides additional context to an inc column.
 type IncGenerator struct {
-	Start  int    `yaml:"start"`
-	Format string `yaml:"format"`
+	Start  int    `yaml:"start,omitempty"`
+	Step   int    `yaml:"step,omitempty"`
+	Format string `yaml:"format,omitempty"`
+
+	// GroupBy are columns of the same table, where each combination of
+	// their values has its own sequence (e.g. line numbers per order).
+	GroupBy []string `yaml:"group_by,omitempty,flow"`
 }
 
 func (pi IncGenerator) GetFormat() string {
@@ -23,11 +31,51 @@ func (g IncGenerator) Generate(t model.Table, c model.Column, files map[string]m
 		}))
 	}
 
+	step := lo.Ternary(g.Step == 0, 1, g.Step)
+
+	groups, err := groupColumns(t, c, "inc", g.GroupBy, files)
+	if err != nil {
+		return err
+	}
+
+	next := map[string]int{}
 	var line []string
 	for i := 0; i < t.Count; i++ {
-		line = append(line, formatValue(g, g.Start+i))
+		key := groupKey(groups, i)
+
+		v, ok := next[key]
+		if !ok {
+			v = g.Start
+		}
+		next[key] = v + step
+
+		line = append(line, formatValue(g, v))
 	}
 
 	AddTable(t, c.Name, line, files)
 	return nil
 }
+
+// groupColumns returns the values of the columns that the values of a column
+// of the given type are grouped by.
+func groupColumns(t model.Table, c model.Column, typ string, names []string, files map[string]model.CSVFile) ([][]string, error) {
+	file := files[t.Name]
+
+	groups := make([][]string, len(names))
+	for i, name := range names {
+		colIndex := lo.IndexOf(file.Header, name)
+		if colIndex == -1 {
+			return nil, fmt.Errorf("column %q must be generated before %s column %q", name, typ, c.Name)
+		}
+		groups[i] = file.Lines[colIndex]
+	}
+
+	return groups, nil
+}
+
+// groupKey returns a key for a row's values of the given columns.
+func groupKey(columns [][]string, row int) string {
+	return strings.Join(lo.Map(columns, func(col []string, _ int) string {
+		return valueAt(col, row)
+	}), "\x00")
+}
@@ -72,3 +72,70 @@ func TestGenerateIncColumn(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateIncColumnGroupBy(t *testing.T) {
+	cases := []struct {
+		name      string
+		generator IncGenerator
+		exp       []string
+		expErr    string
+	}{
+		{
+			name:      "step",
+			generator: IncGenerator{Start: 10, Step: 10},
+			exp:       []string{"10", "20", "30", "40", "50", "60"},
+		},
+		{
+			name:      "negative step",
+			generator: IncGenerator{Start: 3, Step: -1},
+			exp:       []string{"3", "2", "1", "0", "-1", "-2"},
+		},
+		{
+			name:      "group by column",
+			generator: IncGenerator{Start: 1, GroupBy: []string{"order_id"}},
+			exp:       []string{"1", "2", "1", "3", "1", "2"},
+		},
+		{
+			name:      "group by columns",
+			generator: IncGenerator{Start: 1, GroupBy: []string{"order_id", "kind"}},
+			exp:       []string{"1", "1", "1", "2", "1", "1"},
+		},
+		{
+			name:      "group by with step",
+			generator: IncGenerator{Start: 10, Step: 10, GroupBy: []string{"order_id"}},
+			exp:       []string{"10", "20", "10", "30", "10", "20"},
+		},
+		{
+			name:      "missing group by column",
+			generator: IncGenerator{GroupBy: []string{"abc"}},
+			expErr:    `column "abc" must be generated before inc column "line_number"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			table := model.Table{
+				Name: "order_item",
+			}
+
+			files := map[string]model.CSVFile{
+				"order_item": {
+					Name:   "order_item",
+					Header: []string{"order_id", "kind"},
+					Lines: [][]string{
+						{"a", "a", "b", "a", "c", "c"},
+						{"x", "y", "x", "x", "x", "y"},
+					},
+				},
+			}
+
+			err := c.generator.Generate(table, model.Column{Name: "line_number"}, files)
+			if c.expErr != "" {
+				assert.EqualError(t, err, c.expErr)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, c.exp, files["order_item"].Lines[2])
+		})
+	}
+}
@@ -10,10 +10,10 @@ import (
 
 // MatchGenerator provides additional context to a match column.
 type MatchGenerator struct {
-	SourceTable  string `yaml:"source_table"`
-	SourceColumn string `yaml:"source_column"`
-	SourceValue  string `yaml:"source_value"`
-	MatchColumn  string `yaml:"match_column"`
+	SourceTable  string `yaml:"source_table,omitempty"`
+	SourceColumn string `yaml:"source_column,omitempty"`
+	SourceValue  string `yaml:"source_value,omitempty"`
+	MatchColumn  string `yaml:"match_column,omitempty"`
 }
 
 // Generate matches values from a previously generated table and inserts values
@@ -1,6 +1,11 @@
 package generator
 
-import "github.com/brianvoe/gofakeit/v6"
+import (
+	"sort"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/samber/lo"
+)
 
 var (
 	replacements = map[string]func() any{
@@ -175,3 +180,12 @@ var (
 		"${zip}":                         func() any { return gofakeit.Zip() },
 	}
 )
+
+// Placeholders returns the names of all available value placeholders
+// (e.g. "${uuid}") in alphabetical order.
+func Placeholders() []string {
+	placeholders := lo.Keys(replacements)
+	sort.Strings(placeholders)
+
+	return placeholders
+}
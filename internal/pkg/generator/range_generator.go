@@ -2,20 +2,27 @@ package generator
 
 import (
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/codingconcepts/dg/internal/pkg/random"
 	"github.com/samber/lo"
 )
 
 // RangeGenerator provides additional context to a range column.
 type RangeGenerator struct {
-	Type   string `yaml:"type"`
-	From   string `yaml:"from"`
-	To     string `yaml:"to"`
-	Step   string `yaml:"step"`
-	Format string `yaml:"format"`
+	Type        string `yaml:"type,omitempty"`
+	From        string `yaml:"from,omitempty"`
+	To          string `yaml:"to,omitempty"`
+	Step        string `yaml:"step,omitempty"`
+	Format      string `yaml:"format,omitempty"`
+	InputFormat string `yaml:"input_format,omitempty"`
+	Timezone    string `yaml:"timezone,omitempty"`
+	Precision   *int   `yaml:"precision,omitempty"`
+	Shuffle     bool   `yaml:"shuffle,omitempty"`
 }
 
 // Generate sequential data between a given start and end range.
@@ -28,28 +35,35 @@ func (g RangeGenerator) Generate(t model.Table, c model.Column, files map[string
 		count = t.Count
 	}
 
+	var lines []string
+	var err error
+
 	switch g.Type {
-	case "date":
-		lines, err := g.generateDateSlice(count)
-		if err != nil {
-			return fmt.Errorf("generating date slice: %w", err)
+	case "date", "timestamp":
+		if lines, err = g.generateDateSlice(count); err != nil {
+			return fmt.Errorf("generating %s slice: %w", g.Type, err)
 		}
 
-		AddTable(t, c.Name, lines, files)
-		return nil
-
 	case "int":
-		lines, err := g.generateIntSlice(count)
-		if err != nil {
+		if lines, err = g.generateIntSlice(count); err != nil {
 			return fmt.Errorf("generating int slice: %w", err)
 		}
 
-		AddTable(t, c.Name, lines, files)
-		return nil
+	case "float", "decimal":
+		if lines, err = g.generateDecimalSlice(count); err != nil {
+			return fmt.Errorf("generating %s slice: %w", g.Type, err)
+		}
 
 	default:
 		return fmt.Errorf("%q is not a valid range type", g.Type)
 	}
+
+	if g.Shuffle {
+		shuffle(lines)
+	}
+
+	AddTable(t, c.Name, lines, files)
+	return nil
 }
 
 func (g RangeGenerator) generateDateSlice(count int) ([]string, error) {
@@ -58,33 +72,77 @@ func (g RangeGenerator) generateDateSlice(count int) ([]string, error) {
 		return nil, fmt.Errorf("either a count or a step must be provided to a date range generator")
 	}
 
-	from, err := time.Parse(g.Format, g.From)
+	inputFormat, outputFormat := g.dateFormats()
+
+	loc := time.UTC
+	if g.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(g.Timezone); err != nil {
+			return nil, fmt.Errorf("loading timezone: %w", err)
+		}
+	}
+
+	// Dates without a time zone are in the configured time zone.
+	from, err := time.ParseInLocation(inputFormat, g.From, loc)
 	if err != nil {
 		return nil, fmt.Errorf("parsing from date: %w", err)
 	}
 
-	to, err := time.Parse(g.Format, g.To)
+	to, err := time.ParseInLocation(inputFormat, g.To, loc)
 	if err != nil {
 		return nil, fmt.Errorf("parsing to date: %w", err)
 	}
 
-	var step time.Duration
+	// With a count, calculate each value from the start, so that exactly
+	// count values are generated.
+	if count == 1 {
+		return []string{from.In(loc).Format(outputFormat)}, nil
+	}
 	if count > 0 {
-		step = to.Sub(from) / time.Duration(count)
-	} else {
-		if step, err = time.ParseDuration(g.Step); err != nil {
-			return nil, fmt.Errorf("parsing step: %w", err)
+		span, n := to.Sub(from), time.Duration(count-1)
+		s := make([]string, count)
+		for i := range s {
+			offset := span/n*time.Duration(i) + span%n*time.Duration(i)/n
+			s[i] = from.Add(offset).In(loc).Format(outputFormat)
 		}
+		return s, nil
+	}
+
+	step, err := time.ParseDuration(g.Step)
+	if err != nil {
+		return nil, fmt.Errorf("parsing step: %w", err)
+	}
+
+	if step == 0 || (step > 0) != to.After(from) {
+		return nil, fmt.Errorf("step must move from %q towards %q", g.From, g.To)
 	}
 
 	var s []string
-	for i := from; i.Before(to); i = i.Add(step) {
-		s = append(s, i.Format(g.Format))
+	for i := from; (step > 0 && !i.After(to)) || (step < 0 && !i.Before(to)); i = i.Add(step) {
+		s = append(s, i.In(loc).Format(outputFormat))
 	}
 
 	return s, nil
 }
 
+// dateFormats returns the layouts used to parse the from and to dates, and
+// to format the generated dates. Dates are parsed and formatted using the
+// same layout unless an input format is provided, and timestamps default to
+// RFC 3339.
+func (g RangeGenerator) dateFormats() (string, string) {
+	outputFormat := g.Format
+	if outputFormat == "" && g.Type == "timestamp" {
+		outputFormat = time.RFC3339
+	}
+
+	inputFormat := g.InputFormat
+	if inputFormat == "" {
+		inputFormat = lo.Ternary(g.Type == "timestamp", time.RFC3339, outputFormat)
+	}
+
+	return inputFormat, outputFormat
+}
+
 func (g RangeGenerator) generateIntSlice(count int) ([]string, error) {
 	// Validate that we have everything we need.
 	if count == 0 && g.Step == "" {
@@ -105,19 +163,147 @@ func (g RangeGenerator) generateIntSlice(count int) ([]string, error) {
 		}
 	}
 
-	var step int
+	// With a count, calculate each value from the start and round it
+	// afterwards, so that exactly count values are generated.
+	if count == 1 {
+		return []string{strconv.Itoa(from)}, nil
+	}
 	if count > 0 {
-		step = (to - from) / (count - 1)
-	} else {
-		if step, err = strconv.Atoi(g.Step); err != nil {
-			return nil, fmt.Errorf("parsing step number: %w", err)
+		s := make([]string, count)
+		for i := range s {
+			v := math.Round(float64(from) + float64(i)*float64(to-from)/float64(count-1))
+			s[i] = strconv.Itoa(int(v))
 		}
+		return s, nil
+	}
+
+	step, err := strconv.Atoi(g.Step)
+	if err != nil {
+		return nil, fmt.Errorf("parsing step number: %w", err)
+	}
+
+	if step == 0 || (step > 0 && from > to) || (step < 0 && from < to) {
+		return nil, fmt.Errorf("step must move from %d towards %d", from, to)
 	}
 
 	var s []string
-	for i := from; i <= to; i += step {
+	for i := from; (step > 0 && i <= to) || (step < 0 && i >= to); i += step {
 		s = append(s, strconv.Itoa(i))
 	}
 
 	return s, nil
 }
+
+// generateDecimalSlice generates numbers between from and to (inclusive).
+// Decimals are calculated using integers scaled by their precision, so that
+// their values are exact, while floats are calculated as floats.
+func (g RangeGenerator) generateDecimalSlice(count int) ([]string, error) {
+	// Validate that we have everything we need.
+	if count == 0 && g.Step == "" {
+		return nil, fmt.Errorf("either a count or a step must be provided to a %s range generator", g.Type)
+	}
+
+	if g.To == "" {
+		return nil, fmt.Errorf("a to value must be provided to a %s range generator", g.Type)
+	}
+
+	precision := -1
+	if g.Precision != nil {
+		precision = *g.Precision
+	} else if g.Type == "decimal" {
+		precision = lo.Max([]int{decimalPlaces(g.From), decimalPlaces(g.To), decimalPlaces(g.Step)})
+	}
+
+	if precision < 0 && g.Type == "decimal" {
+		return nil, fmt.Errorf("precision must be 0 or greater")
+	}
+
+	from, err := strconv.ParseFloat(g.From, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing from number: %w", err)
+	}
+
+	to, err := strconv.ParseFloat(g.To, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing to number: %w", err)
+	}
+
+	// Decimals are calculated as integers, scaled by their precision.
+	scale := 1.0
+	if g.Type == "decimal" {
+		scale = math.Pow(10, float64(precision))
+		from, to = math.Round(from*scale), math.Round(to*scale)
+	}
+
+	// With a count, calculate each value from the start and round it
+	// afterwards, so that exactly count values are generated.
+	if count == 1 {
+		return []string{g.formatDecimal(from/scale, precision)}, nil
+	}
+	if count > 0 {
+		s := make([]string, count)
+		for i := range s {
+			v := from + float64(i)*(to-from)/float64(count-1)
+			if g.Type == "decimal" {
+				v = math.Round(v)
+			}
+			s[i] = g.formatDecimal(v/scale, precision)
+		}
+		return s, nil
+	}
+
+	step, err := strconv.ParseFloat(g.Step, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing step number: %w", err)
+	}
+
+	if step == 0 || (step > 0 && from > to) || (step < 0 && from < to) {
+		return nil, fmt.Errorf("step must move from %s towards %s", g.From, g.To)
+	}
+
+	if g.Type == "decimal" {
+		step = math.Round(step * scale)
+		if step == 0 {
+			return nil, fmt.Errorf("step is smaller than the precision")
+		}
+
+		var s []string
+		for i := from; (step > 0 && i <= to) || (step < 0 && i >= to); i += step {
+			s = append(s, g.formatDecimal(i/scale, precision))
+		}
+		return s, nil
+	}
+
+	// Calculate each value from the start, rather than accumulating the
+	// step, to avoid accumulating rounding errors.
+	s := make([]string, int(math.Floor((to-from)/step+1e-9))+1)
+	for i := range s {
+		s[i] = g.formatDecimal(from+float64(i)*step, precision)
+	}
+
+	return s, nil
+}
+
+func (g RangeGenerator) formatDecimal(v float64, precision int) string {
+	if g.Format != "" {
+		return fmt.Sprintf(g.Format, v)
+	}
+
+	return strconv.FormatFloat(v, 'f', precision, 64)
+}
+
+// decimalPlaces returns the number of digits after the decimal point.
+func decimalPlaces(s string) int {
+	if i := strings.IndexByte(s, '.'); i != -1 {
+		return len(s) - i - 1
+	}
+	return 0
+}
+
+// shuffle randomly reorders a slice in place.
+func shuffle[T any](s []T) {
+	for i := len(s) - 1; i > 0; i-- {
+		j := random.Intn(i + 1)
+		s[i], s[j] = s[j], s[i]
+	}
+}
@@ -1,9 +1,12 @@
 package generator
 
 import (
+	"strconv"
 	"testing"
 
 	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/codingconcepts/dg/internal/pkg/random"
+	"github.com/samber/lo"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -40,8 +43,8 @@ func TestGenerateRangeColumn(t *testing.T) {
 			format: "2006-01-02",
 			expLines: []string{
 				"2023-01-01",
-				"2023-01-11",
-				"2023-01-21",
+				"2023-01-16",
+				"2023-02-01",
 			},
 		},
 		{
@@ -55,9 +58,9 @@ func TestGenerateRangeColumn(t *testing.T) {
 			format: "2006-01-02",
 			expLines: []string{
 				"2023-01-01",
-				"2023-01-08",
-				"2023-01-16",
-				"2023-01-24",
+				"2023-01-11",
+				"2023-01-21",
+				"2023-02-01",
 			},
 		},
 		{
@@ -208,7 +211,7 @@ func TestGenerateDateSlice(t *testing.T) {
 			to:     "2023-01-10",
 			format: "2006-01-02",
 			expSlice: []string{
-				"2023-01-01", "2023-01-01", "2023-01-02", "2023-01-03", "2023-01-04", "2023-01-05", "2023-01-06", "2023-01-07", "2023-01-08", "2023-01-09",
+				"2023-01-01", "2023-01-02", "2023-01-03", "2023-01-04", "2023-01-05", "2023-01-06", "2023-01-07", "2023-01-08", "2023-01-09", "2023-01-10",
 			},
 		},
 		{
@@ -218,7 +221,7 @@ func TestGenerateDateSlice(t *testing.T) {
 			to:     "2023-01-20",
 			format: "2006-01-02",
 			expSlice: []string{
-				"2023-01-10", "2023-01-11", "2023-01-12", "2023-01-13", "2023-01-14", "2023-01-15", "2023-01-16", "2023-01-17", "2023-01-18", "2023-01-19",
+				"2023-01-10", "2023-01-11", "2023-01-12", "2023-01-13", "2023-01-14", "2023-01-15", "2023-01-16", "2023-01-17", "2023-01-18", "2023-01-19", "2023-01-20",
 			},
 		},
 		{
@@ -275,3 +278,319 @@ func TestGenerateDateSlice(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateDateSliceTimestamp(t *testing.T) {
+	cases := []struct {
+		name        string
+		from        string
+		to          string
+		step        string
+		inputFormat string
+		format      string
+		timezone    string
+		expSlice    []string
+		expError    string
+	}{
+		{
+			name:     "defaults to rfc3339",
+			from:     "2023-01-01T00:00:00Z",
+			to:       "2023-01-01T03:00:00Z",
+			step:     "1h",
+			expSlice: []string{"2023-01-01T00:00:00Z", "2023-01-01T01:00:00Z", "2023-01-01T02:00:00Z", "2023-01-01T03:00:00Z"},
+		},
+		{
+			name:        "input and output formats",
+			from:        "2023-01-01 00:00",
+			to:          "2023-01-01 01:00",
+			step:        "30m",
+			inputFormat: "2006-01-02 15:04",
+			format:      "15:04:05",
+			expSlice:    []string{"00:00:00", "00:30:00", "01:00:00"},
+		},
+		{
+			name:     "timezone",
+			from:     "2023-01-01T00:00:00Z",
+			to:       "2023-01-01T02:00:00Z",
+			step:     "1h",
+			timezone: "America/New_York",
+			expSlice: []string{"2022-12-31T19:00:00-05:00", "2022-12-31T20:00:00-05:00", "2022-12-31T21:00:00-05:00"},
+		},
+		{
+			name:     "descending",
+			from:     "2023-01-01T03:00:00Z",
+			to:       "2023-01-01T00:00:00Z",
+			step:     "-1h",
+			expSlice: []string{"2023-01-01T03:00:00Z", "2023-01-01T02:00:00Z", "2023-01-01T01:00:00Z", "2023-01-01T00:00:00Z"},
+		},
+		{
+			name:     "step away from to",
+			from:     "2023-01-01T00:00:00Z",
+			to:       "2023-01-01T03:00:00Z",
+			step:     "-1h",
+			expError: `step must move from "2023-01-01T00:00:00Z" towards "2023-01-01T03:00:00Z"`,
+		},
+		{
+			name:     "invalid timezone",
+			from:     "2023-01-01T00:00:00Z",
+			to:       "2023-01-01T03:00:00Z",
+			step:     "1h",
+			timezone: "abc",
+			expError: "loading timezone: unknown time zone abc",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			g := RangeGenerator{
+				Type:        "timestamp",
+				From:        c.from,
+				To:          c.to,
+				Step:        c.step,
+				InputFormat: c.inputFormat,
+				Format:      c.format,
+				Timezone:    c.timezone,
+			}
+
+			actSlice, actErr := g.generateDateSlice(0)
+			if c.expError != "" {
+				assert.Equal(t, c.expError, actErr.Error())
+				return
+			}
+
+			assert.Nil(t, actErr)
+			assert.Equal(t, c.expSlice, actSlice)
+		})
+	}
+}
+
+func TestGenerateIntSliceDescending(t *testing.T) {
+	g := RangeGenerator{From: "10", To: "1", Step: "-3"}
+
+	actSlice, actErr := g.generateIntSlice(0)
+	assert.Nil(t, actErr)
+	assert.Equal(t, []string{"10", "7", "4", "1"}, actSlice)
+
+	g.Step = "3"
+	_, actErr = g.generateIntSlice(0)
+	assert.Equal(t, "step must move from 10 towards 1", actErr.Error())
+}
+
+func TestGenerateRangeColumnCount(t *testing.T) {
+	cases := []struct {
+		name  string
+		rtype string
+		from  string
+		to    string
+		count int
+		exp   []string
+	}{
+		{
+			name:  "date",
+			rtype: "date",
+			from:  "2023-01-01",
+			to:    "2023-02-01",
+			count: 7,
+		},
+		{
+			name:  "timestamp",
+			rtype: "timestamp",
+			from:  "2023-01-01T00:00:00Z",
+			to:    "2023-01-01T01:00:00Z",
+			count: 7,
+		},
+		{
+			name:  "descending timestamp",
+			rtype: "timestamp",
+			from:  "2023-01-01T01:00:00Z",
+			to:    "2023-01-01T00:00:00Z",
+			count: 7,
+		},
+		{
+			name:  "int",
+			rtype: "int",
+			from:  "1",
+			to:    "10",
+			count: 6,
+			exp:   []string{"1", "3", "5", "6", "8", "10"},
+		},
+		{
+			name:  "descending int",
+			rtype: "int",
+			from:  "10",
+			to:    "1",
+			count: 4,
+			exp:   []string{"10", "7", "4", "1"},
+		},
+		{
+			name:  "float",
+			rtype: "float",
+			from:  "0",
+			to:    "1",
+			count: 7,
+		},
+		{
+			name:  "decimal",
+			rtype: "decimal",
+			from:  "0.00",
+			to:    "1.00",
+			count: 7,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			g := RangeGenerator{
+				Type:   c.rtype,
+				From:   c.from,
+				To:     c.to,
+				Format: lo.Ternary(c.rtype == "date", "2006-01-02", ""),
+			}
+
+			files := map[string]model.CSVFile{}
+			err := g.Generate(model.Table{Name: "table", Count: c.count}, model.Column{Name: "col"}, files)
+			assert.Nil(t, err)
+
+			lines := files["table"].Lines[0]
+			assert.Len(t, lines, c.count)
+			assert.Equal(t, c.from, lines[0])
+			assert.Equal(t, c.to, lines[len(lines)-1])
+			if c.exp != nil {
+				assert.Equal(t, c.exp, lines)
+			}
+		})
+	}
+}
+
+func TestGenerateDecimalSlice(t *testing.T) {
+	cases := []struct {
+		name      string
+		rtype     string
+		from      string
+		to        string
+		step      string
+		count     int
+		precision *int
+		format    string
+		expSlice  []string
+		expError  string
+	}{
+		{
+			name:     "decimal step",
+			rtype:    "decimal",
+			from:     "0.1",
+			to:       "0.5",
+			step:     "0.1",
+			expSlice: []string{"0.1", "0.2", "0.3", "0.4", "0.5"},
+		},
+		{
+			name:      "decimal precision",
+			rtype:     "decimal",
+			from:      "1",
+			to:        "2",
+			step:      "0.25",
+			precision: lo.ToPtr(3),
+			expSlice:  []string{"1.000", "1.250", "1.500", "1.750", "2.000"},
+		},
+		{
+			name:     "decimal descending",
+			rtype:    "decimal",
+			from:     "1.5",
+			to:       "0",
+			step:     "-0.5",
+			expSlice: []string{"1.5", "1.0", "0.5", "0.0"},
+		},
+		{
+			name:     "decimal count",
+			rtype:    "decimal",
+			from:     "0.00",
+			to:       "1.00",
+			count:    5,
+			expSlice: []string{"0.00", "0.25", "0.50", "0.75", "1.00"},
+		},
+		{
+			name:     "decimal count with inexact step",
+			rtype:    "decimal",
+			from:     "0.00",
+			to:       "1.00",
+			count:    7,
+			expSlice: []string{"0.00", "0.17", "0.33", "0.50", "0.67", "0.83", "1.00"},
+		},
+		{
+			name:      "decimal step smaller than precision",
+			rtype:     "decimal",
+			from:      "0",
+			to:        "1",
+			step:      "0.01",
+			precision: lo.ToPtr(1),
+			expError:  "step is smaller than the precision",
+		},
+		{
+			name:     "float count",
+			rtype:    "float",
+			from:     "0",
+			to:       "1",
+			count:    3,
+			expSlice: []string{"0", "0.5", "1"},
+		},
+		{
+			name:     "float format",
+			rtype:    "float",
+			from:     "0",
+			to:       "0.3",
+			step:     "0.1",
+			format:   "%.2f",
+			expSlice: []string{"0.00", "0.10", "0.20", "0.30"},
+		},
+		{
+			name:     "float without to",
+			rtype:    "float",
+			from:     "0",
+			step:     "0.1",
+			expError: "a to value must be provided to a float range generator",
+		},
+		{
+			name:     "float zero step",
+			rtype:    "float",
+			from:     "0",
+			to:       "1",
+			step:     "0",
+			expError: "step must move from 0 towards 1",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			g := RangeGenerator{
+				Type:      c.rtype,
+				From:      c.from,
+				To:        c.to,
+				Step:      c.step,
+				Precision: c.precision,
+				Format:    c.format,
+			}
+
+			actSlice, actErr := g.generateDecimalSlice(c.count)
+			if c.expError != "" {
+				assert.Equal(t, c.expError, actErr.Error())
+				return
+			}
+
+			assert.Nil(t, actErr)
+			assert.Equal(t, c.expSlice, actSlice)
+		})
+	}
+}
+
+func TestGenerateRangeShuffle(t *testing.T) {
+	random.Seed(1)
+
+	table := model.Table{Name: "table", Count: 20}
+	files := map[string]model.CSVFile{}
+
+	g := RangeGenerator{Type: "int", From: "1", Shuffle: true}
+	assert.Nil(t, g.Generate(table, model.Column{Name: "col"}, files))
+
+	act := files["table"].Lines[0]
+	assert.NotEqual(t, lo.Map(lo.Range(20), func(i, _ int) string { return strconv.Itoa(i + 1) }), act)
+	assert.ElementsMatch(t, lo.Map(lo.Range(20), func(i, _ int) string { return strconv.Itoa(i + 1) }), act)
+}
@@ -2,6 +2,7 @@ package generator
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/codingconcepts/dg/internal/pkg/model"
 	"github.com/codingconcepts/dg/internal/pkg/random"
@@ -11,8 +12,74 @@ import (
 
 // RefGenerator provides additional context to a ref column.
 type RefGenerator struct {
-	Table  string `yaml:"table"`
-	Column string `yaml:"column"`
+	Table  string `yaml:"table,omitempty"`
+	Column string `yaml:"column,omitempty"`
+
+	// Distribution determines how often each parent is referenced, and is
+	// either "uniform" (the default), "zipf", or "normal". S configures the
+	// skew of a zipf distribution, and StdDev the spread of a normal
+	// distribution, as a proportion of the number of parents.
+	Distribution string   `yaml:"distribution,omitempty"`
+	S            float64  `yaml:"s,omitempty"`
+	StdDev       *float64 `yaml:"stddev,omitempty"`
+
+	MinPerParent int `yaml:"min_per_parent,omitempty"`
+	MaxPerParent int `yaml:"max_per_parent,omitempty"`
+
+	// Coverage of "all" ensures every parent is referenced at least once.
+	Coverage string `yaml:"coverage,omitempty"`
+
+	// Distinct ensures no parent is referenced more than once, for one-to-one
+	// relationships.
+	Distinct bool `yaml:"distinct,omitempty"`
+
+	// Columns are additional columns to copy from the same referenced row,
+	// for correlated values (e.g. an address's city and zip) and composite
+	// foreign keys.
+	Columns []RefColumn `yaml:"columns,omitempty"`
+}
+
+// RefColumn copies a column of a referenced row into another column.
+type RefColumn struct {
+	// Column is the column of the referenced table to copy.
+	Column string `yaml:"column,omitempty"`
+
+	// Name is the name of the column to copy it into (by default, Column).
+	Name string `yaml:"name,omitempty"`
+}
+
+// Names returns the names of the columns a ref column generates, which are
+// its own name, followed by the names of any additional columns, each of
+// which must be different.
+func (g RefGenerator) Names(name string) ([]string, error) {
+	names := append([]string{name}, lo.Map(g.Columns, func(rc RefColumn, _ int) string {
+		return lo.Ternary(rc.Name == "", rc.Column, rc.Name)
+	})...)
+
+	if duplicates := lo.FindDuplicates(names); len(duplicates) > 0 {
+		return nil, fmt.Errorf("ref column %q generates more than one column called %q", name, duplicates[0])
+	}
+
+	return names, nil
+}
+
+// ColumnNames returns the names of the columns generated by a column, which
+// for ref columns include the additional columns copied from the same row.
+func ColumnNames(c model.Column) []string {
+	if c.Type != "ref" {
+		return []string{c.Name}
+	}
+
+	var g RefGenerator
+	if err := c.Generator.UnmarshalFunc(&g); err != nil {
+		return []string{c.Name}
+	}
+
+	names, err := g.Names(c.Name)
+	if err != nil {
+		return []string{c.Name}
+	}
+	return names
 }
 
 // Generate looks to previously generated table data and references that when generating data
@@ -24,19 +91,212 @@ func (g RefGenerator) Generate(t model.Table, c model.Column, files map[string]m
 		}))
 	}
 
+	names, err := g.Names(c.Name)
+	if err != nil {
+		return err
+	}
+
+	// Additional columns can't share the name of another of the table's
+	// columns.
+	for _, name := range names[1:] {
+		other := lo.SomeBy(t.Columns, func(oc model.Column) bool {
+			return oc.Name != c.Name && lo.Contains(ColumnNames(oc), name)
+		})
+		if other || lo.Contains(files[t.Name].Header, name) {
+			return fmt.Errorf("ref column %q copies a column into %q, which is already a column of table %q", c.Name, name, t.Name)
+		}
+	}
+
 	table, ok := files[g.Table]
 	if !ok {
 		return fmt.Errorf("missing table %q for ref lookup", g.Table)
 	}
 
-	colIndex := lo.IndexOf(table.Header, g.Column)
-	column := table.Lines[colIndex]
+	sources := append([]string{g.Column}, lo.Map(g.Columns, func(rc RefColumn, _ int) string {
+		return rc.Column
+	})...)
 
-	var line []string
-	for i := 0; i < t.Count; i++ {
-		line = append(line, column[random.Intn(len(column))])
+	columns := make([][]string, len(sources))
+	for i, source := range sources {
+		colIndex := lo.IndexOf(table.Header, source)
+		if colIndex == -1 {
+			return fmt.Errorf("missing column %q in table %q for ref lookup", source, g.Table)
+		}
+		columns[i] = table.Lines[colIndex]
+	}
+
+	parents, err := g.parents(t.Count, len(columns[0]))
+	if err != nil {
+		return err
+	}
+
+	for i, name := range names {
+		line := lo.Map(parents, func(p, _ int) string {
+			return valueAt(columns[i], p)
+		})
+		AddTable(t, name, line, files)
 	}
 
-	AddTable(t, c.Name, line, files)
 	return nil
 }
+
+// parents returns the index of the parent that each row references.
+func (g RefGenerator) parents(rows, parents int) ([]int, error) {
+	if parents == 0 {
+		if rows > 0 {
+			return nil, fmt.Errorf("no values in %s.%s to reference", g.Table, g.Column)
+		}
+
+		return nil, nil
+	}
+
+	pick, err := g.picker(parents)
+	if err != nil {
+		return nil, err
+	}
+
+	minPerParent := g.MinPerParent
+	switch g.Coverage {
+	case "":
+	case "all":
+		minPerParent = lo.Max([]int{minPerParent, 1})
+	default:
+		return nil, fmt.Errorf("%q is not a valid coverage", g.Coverage)
+	}
+
+	if g.Distinct {
+		if g.MaxPerParent > 1 {
+			return nil, fmt.Errorf("max_per_parent can't be greater than 1 for distinct references")
+		}
+
+		if rows > parents {
+			return nil, fmt.Errorf("%d rows can't each reference a different one of the %d values in %s.%s", rows, parents, g.Table, g.Column)
+		}
+
+		g.MaxPerParent = 1
+	}
+
+	// Without per-parent limits, parents can be picked row by row.
+	if minPerParent == 0 && g.MaxPerParent == 0 {
+		indexes := make([]int, rows)
+		for i := range indexes {
+			indexes[i] = pick()
+		}
+
+		return indexes, nil
+	}
+
+	counts, err := g.parentCounts(rows, parents, minPerParent, pick)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make([]int, 0, rows)
+	for i, n := range counts {
+		for j := 0; j < n; j++ {
+			indexes = append(indexes, i)
+		}
+	}
+	shuffle(indexes)
+
+	return indexes, nil
+}
+
+// picker returns a function that picks the index of a parent according to
+// the generator's distribution. Parents are ranked in a random order, so that
+// the most referenced parents aren't always the first ones generated.
+func (g RefGenerator) picker(parents int) (func() int, error) {
+	switch g.Distribution {
+	case "", "uniform":
+		return func() int {
+			return random.Intn(parents)
+		}, nil
+
+	case "zipf":
+		s := lo.Ternary(g.S == 0, 1.5, g.S)
+		if s <= 1 {
+			return nil, fmt.Errorf("zipf distribution requires an 's' greater than 1")
+		}
+
+		rank := permutation(parents)
+		zipf := random.NewZipf(s, 1, uint64(parents-1))
+		return func() int {
+			return rank[zipf.Uint64()]
+		}, nil
+
+	case "normal":
+		stddev := lo.FromPtrOr(g.StdDev, 0.15)
+		if stddev <= 0 {
+			return nil, fmt.Errorf("normal distribution requires a 'stddev' greater than 0")
+		}
+
+		rank := permutation(parents)
+		mean := float64(parents-1) / 2
+		return func() int {
+			for {
+				i := int(math.Round(mean + stddev*float64(parents)*random.NormFloat64()))
+				if i >= 0 && i < parents {
+					return rank[i]
+				}
+			}
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("%q is not a valid ref distribution", g.Distribution)
+	}
+}
+
+// parentCounts returns the number of times each parent is referenced, with
+// every parent referenced between minPerParent and g.MaxPerParent times.
+func (g RefGenerator) parentCounts(rows, parents, minPerParent int, pick func() int) ([]int, error) {
+	if g.MaxPerParent != 0 && g.MaxPerParent < minPerParent {
+		return nil, fmt.Errorf("max_per_parent must be greater than or equal to min_per_parent")
+	}
+
+	if rows < parents*minPerParent {
+		return nil, fmt.Errorf("%d rows can't reference each of the %d parents at least %d times", rows, parents, minPerParent)
+	}
+
+	if g.MaxPerParent != 0 && rows > parents*g.MaxPerParent {
+		return nil, fmt.Errorf("%d rows can't reference each of the %d parents at most %d times", rows, parents, g.MaxPerParent)
+	}
+
+	counts := make([]int, parents)
+	for i := range counts {
+		counts[i] = minPerParent
+	}
+
+	// Keep track of the parents that can be referenced again, so that rows
+	// picking a parent that's reached its maximum can be given another one.
+	full := func(p int) bool {
+		return g.MaxPerParent != 0 && counts[p] >= g.MaxPerParent
+	}
+
+	available := lo.Reject(lo.Range(parents), func(p, _ int) bool { return full(p) })
+	position := make([]int, parents)
+	for i, p := range available {
+		position[p] = i
+	}
+
+	for remaining := rows - parents*minPerParent; remaining > 0; remaining-- {
+		p := pick()
+		if full(p) {
+			p = available[random.Intn(len(available))]
+		}
+
+		if counts[p]++; full(p) {
+			last := available[len(available)-1]
+			available[position[p]], position[last] = last, position[p]
+			available = available[:len(available)-1]
+		}
+	}
+
+	return counts, nil
+}
+
+// permutation returns the numbers from 0 to n-1 in a random order.
+func permutation(n int) []int {
+	p := lo.Range(n)
+	shuffle(p)
+	return p
+}
@@ -1,9 +1,11 @@
 package generator
 
 import (
+	"strconv"
 	"testing"
 
 	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/samber/lo"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -34,3 +36,243 @@ func TestGenerateRefColumn(t *testing.T) {
 	assert.Equal(t, "ce9af887-37eb-4e08-9790-4f481b0fa594", files["pet"].Lines[0][0])
 	assert.Equal(t, "ce9af887-37eb-4e08-9790-4f481b0fa594", files["pet"].Lines[0][1])
 }
+
+func TestGenerateRefColumnCardinality(t *testing.T) {
+	parents := lo.Map(lo.Range(10), func(i, _ int) string { return strconv.Itoa(i) })
+
+	cases := []struct {
+		name      string
+		count     int
+		generator RefGenerator
+		expShape  func(t *testing.T, counts map[string]int)
+		expErr    string
+	}{
+		{
+			name:      "zipf",
+			count:     10000,
+			generator: RefGenerator{Distribution: "zipf", S: 2},
+			expShape: func(t *testing.T, counts map[string]int) {
+				// The hottest parent is referenced far more than the average.
+				assert.Greater(t, lo.Max(lo.Values(counts)), 5000)
+			},
+		},
+		{
+			name:      "normal",
+			count:     10000,
+			generator: RefGenerator{Distribution: "normal", StdDev: lo.ToPtr(0.1)},
+			expShape: func(t *testing.T, counts map[string]int) {
+				assert.Greater(t, lo.Max(lo.Values(counts)), 2000)
+			},
+		},
+		{
+			name:      "coverage all",
+			count:     10,
+			generator: RefGenerator{Coverage: "all"},
+			expShape: func(t *testing.T, counts map[string]int) {
+				assert.Len(t, counts, 10)
+			},
+		},
+		{
+			name:      "min and max per parent",
+			count:     50,
+			generator: RefGenerator{Distribution: "zipf", MinPerParent: 2, MaxPerParent: 8},
+			expShape: func(t *testing.T, counts map[string]int) {
+				assert.Len(t, counts, 10)
+				for _, n := range counts {
+					assert.GreaterOrEqual(t, n, 2)
+					assert.LessOrEqual(t, n, 8)
+				}
+			},
+		},
+		{
+			name:      "min and max per parent equal",
+			count:     30,
+			generator: RefGenerator{MinPerParent: 3, MaxPerParent: 3},
+			expShape: func(t *testing.T, counts map[string]int) {
+				for _, n := range counts {
+					assert.Equal(t, 3, n)
+				}
+			},
+		},
+		{
+			name:      "distinct",
+			count:     10,
+			generator: RefGenerator{Distinct: true},
+			expShape: func(t *testing.T, counts map[string]int) {
+				assert.Len(t, counts, 10)
+			},
+		},
+		{
+			name:      "distinct partial coverage",
+			count:     6,
+			generator: RefGenerator{Distinct: true, Distribution: "zipf"},
+			expShape: func(t *testing.T, counts map[string]int) {
+				assert.Len(t, counts, 6)
+			},
+		},
+		{
+			name:      "too many rows for distinct",
+			count:     11,
+			generator: RefGenerator{Distinct: true},
+			expErr:    "11 rows can't each reference a different one of the 10 values in parent.id",
+		},
+		{
+			name:      "distinct with max per parent",
+			count:     5,
+			generator: RefGenerator{Distinct: true, MaxPerParent: 2},
+			expErr:    "max_per_parent can't be greater than 1 for distinct references",
+		},
+		{
+			name:      "too few rows for coverage",
+			count:     5,
+			generator: RefGenerator{Coverage: "all"},
+			expErr:    "5 rows can't reference each of the 10 parents at least 1 times",
+		},
+		{
+			name:      "too many rows for max per parent",
+			count:     50,
+			generator: RefGenerator{MaxPerParent: 4},
+			expErr:    "50 rows can't reference each of the 10 parents at most 4 times",
+		},
+		{
+			name:      "invalid distribution",
+			count:     5,
+			generator: RefGenerator{Distribution: "abc"},
+			expErr:    `"abc" is not a valid ref distribution`,
+		},
+		{
+			name:      "zero normal stddev",
+			count:     5,
+			generator: RefGenerator{Distribution: "normal", StdDev: lo.ToPtr(0.0)},
+			expErr:    "normal distribution requires a 'stddev' greater than 0",
+		},
+		{
+			name:      "negative normal stddev",
+			count:     5,
+			generator: RefGenerator{Distribution: "normal", StdDev: lo.ToPtr(-0.1)},
+			expErr:    "normal distribution requires a 'stddev' greater than 0",
+		},
+		{
+			name:      "invalid coverage",
+			count:     5,
+			generator: RefGenerator{Coverage: "abc"},
+			expErr:    `"abc" is not a valid coverage`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			files := map[string]model.CSVFile{
+				"parent": {Header: []string{"id"}, Lines: [][]string{parents}},
+			}
+
+			c.generator.Table = "parent"
+			c.generator.Column = "id"
+
+			err := c.generator.Generate(model.Table{Name: "child", Count: c.count}, model.Column{Name: "parent_id"}, files)
+			if c.expErr != "" {
+				assert.Equal(t, c.expErr, err.Error())
+				return
+			}
+			assert.Nil(t, err)
+
+			values := files["child"].Lines[0]
+			assert.Len(t, values, c.count)
+			c.expShape(t, lo.CountValues(values))
+		})
+	}
+}
+
+func TestGenerateRefColumnColumns(t *testing.T) {
+	cases := []struct {
+		name      string
+		generator RefGenerator
+		columns   []model.Column
+		expHeader []string
+		expErr    string
+	}{
+		{
+			name: "copies columns from the same row",
+			generator: RefGenerator{
+				Columns: []RefColumn{{Column: "state"}, {Column: "zip", Name: "postcode"}},
+			},
+			expHeader: []string{"city", "state", "postcode"},
+		},
+		{
+			name: "distinct",
+			generator: RefGenerator{
+				Distinct: true,
+				Columns:  []RefColumn{{Column: "state"}, {Column: "zip"}},
+			},
+			expHeader: []string{"city", "state", "zip"},
+		},
+		{
+			name: "missing column",
+			generator: RefGenerator{
+				Columns: []RefColumn{{Column: "abc"}},
+			},
+			expErr: `missing column "abc" in table "address" for ref lookup`,
+		},
+		{
+			name: "name of the ref column",
+			generator: RefGenerator{
+				Columns: []RefColumn{{Column: "state", Name: "city"}},
+			},
+			expErr: `ref column "city" generates more than one column called "city"`,
+		},
+		{
+			name: "duplicate names",
+			generator: RefGenerator{
+				Columns: []RefColumn{{Column: "state"}, {Column: "zip", Name: "state"}},
+			},
+			expErr: `ref column "city" generates more than one column called "state"`,
+		},
+		{
+			name: "name of another column",
+			generator: RefGenerator{
+				Columns: []RefColumn{{Column: "state"}},
+			},
+			columns: []model.Column{{Name: "city", Type: "ref"}, {Name: "state", Type: "gen"}},
+			expErr:  `ref column "city" copies a column into "state", which is already a column of table "person"`,
+		},
+	}
+
+	address := map[string][]string{
+		"city":  {"Austin", "Boston", "Chicago"},
+		"state": {"TX", "MA", "IL"},
+		"zip":   {"73301", "02108", "60601"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			files := map[string]model.CSVFile{
+				"address": {
+					Header: []string{"city", "state", "zip"},
+					Lines:  [][]string{address["city"], address["state"], address["zip"]},
+				},
+			}
+
+			c.generator.Table = "address"
+			c.generator.Column = "city"
+
+			table := model.Table{Name: "person", Count: 3, Columns: c.columns}
+
+			err := c.generator.Generate(table, model.Column{Name: "city"}, files)
+			if c.expErr != "" {
+				assert.EqualError(t, err, c.expErr)
+				return
+			}
+			assert.Nil(t, err)
+
+			file := files["person"]
+			assert.Equal(t, c.expHeader, file.Header)
+
+			// Every row's values come from the same address.
+			for row, city := range file.Lines[0] {
+				i := lo.IndexOf(address["city"], city)
+				assert.Equal(t, address["state"][i], file.Lines[1][row])
+				assert.Equal(t, address["zip"][i], file.Lines[2][row])
+			}
+		})
+	}
+}
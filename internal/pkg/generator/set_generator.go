@@ -10,8 +10,16 @@ import (
 
 // SetGenerator provides additional context to a set column.
 type SetGenerator struct {
-	Values  []string `yaml:"values"`
-	Weights []int    `yaml:"weights"`
+	Values  []string  `yaml:"values,omitempty,flow"`
+	Weights []int     `yaml:"weights,omitempty,flow"`
+	Given   *SetGiven `yaml:"given,omitempty"`
+}
+
+// SetGiven selects the weights of a set's values based on the value of
+// another column in the same row.
+type SetGiven struct {
+	Column  string           `yaml:"column,omitempty"`
+	Weights map[string][]int `yaml:"weights,omitempty"`
 }
 
 // Generate selects between a set of values for a given table.
@@ -28,19 +36,27 @@ func (g SetGenerator) Generate(t model.Table, c model.Column, files map[string]m
 		count = t.Count
 	}
 
-	var line []string
+	choose := func() string {
+		return g.Values[random.Intn(len(g.Values))]
+	}
+
 	if len(g.Weights) > 0 {
-		items, err := g.buildWeightedItems()
+		items, err := g.buildWeightedItems(g.Weights)
 		if err != nil {
 			return fmt.Errorf("making weighted items collection: %w", err)
 		}
+		choose = items.choose
+	}
 
-		for i := 0; i < count; i++ {
-			line = append(line, items.choose())
+	var line []string
+	if g.Given != nil {
+		var err error
+		if line, err = g.generateGiven(t, c, files, count, choose); err != nil {
+			return err
 		}
 	} else {
 		for i := 0; i < count; i++ {
-			line = append(line, g.Values[random.Intn(len(g.Values))])
+			line = append(line, choose())
 		}
 	}
 
@@ -48,8 +64,52 @@ func (g SetGenerator) Generate(t model.Table, c model.Column, files map[string]m
 	return nil
 }
 
-func (g SetGenerator) buildWeightedItems() (weightedItems, error) {
-	if len(g.Values) != len(g.Weights) {
+// generateGiven selects values using the weights given for the value of
+// another column in each row, falling back to the set's own weights for
+// values without any.
+func (g SetGenerator) generateGiven(t model.Table, c model.Column, files map[string]model.CSVFile, count int, fallback func() string) ([]string, error) {
+	if g.Given.Column == "" {
+		return nil, fmt.Errorf("given must have a 'column'")
+	}
+
+	if len(g.Given.Weights) == 0 {
+		return nil, fmt.Errorf("given must have 'weights'")
+	}
+
+	file := files[t.Name]
+	colIndex := lo.IndexOf(file.Header, g.Given.Column)
+	if colIndex == -1 {
+		return nil, fmt.Errorf("column %q must be generated before set column %q", g.Given.Column, c.Name)
+	}
+	given := file.Lines[colIndex]
+
+	itemsByValue := map[string]weightedItems{}
+	for v, weights := range g.Given.Weights {
+		if lo.Sum(weights) <= 0 {
+			return nil, fmt.Errorf("weights for %q must add up to more than 0", v)
+		}
+
+		items, err := g.buildWeightedItems(weights)
+		if err != nil {
+			return nil, fmt.Errorf("making weighted items collection for %q: %w", v, err)
+		}
+		itemsByValue[v] = items
+	}
+
+	line := make([]string, count)
+	for i := range line {
+		if items, ok := itemsByValue[valueAt(given, i)]; ok {
+			line[i] = items.choose()
+		} else {
+			line[i] = fallback()
+		}
+	}
+
+	return line, nil
+}
+
+func (g SetGenerator) buildWeightedItems(weights []int) (weightedItems, error) {
+	if len(g.Values) != len(weights) {
 		return weightedItems{}, fmt.Errorf("set values and weights need to be the same")
 	}
 
@@ -57,7 +117,7 @@ func (g SetGenerator) buildWeightedItems() (weightedItems, error) {
 	for i, v := range g.Values {
 		weightedItems = append(weightedItems, weightedItem{
 			Value:  v,
-			Weight: g.Weights[i],
+			Weight: weights[i],
 		})
 	}
 
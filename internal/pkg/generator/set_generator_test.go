@@ -36,3 +36,107 @@ func TestGenerateSetColumn(t *testing.T) {
 		files["table"].Lines,
 	)
 }
+
+func TestGenerateSetColumnGiven(t *testing.T) {
+	cases := []struct {
+		name      string
+		generator SetGenerator
+		exp       []string
+		expErr    string
+	}{
+		{
+			name: "weights given value",
+			generator: SetGenerator{
+				Values: []string{"admin", "regular"},
+				Given: &SetGiven{
+					Column: "person_type",
+					Weights: map[string][]int{
+						"employee": {1, 0},
+						"customer": {0, 1},
+					},
+				},
+			},
+			exp: []string{"admin", "regular", "admin"},
+		},
+		{
+			name: "fallback to weights",
+			generator: SetGenerator{
+				Values:  []string{"admin", "regular"},
+				Weights: []int{0, 1},
+				Given: &SetGiven{
+					Column: "person_type",
+					Weights: map[string][]int{
+						"employee": {1, 0},
+					},
+				},
+			},
+			exp: []string{"admin", "regular", "admin"},
+		},
+		{
+			name: "missing column",
+			generator: SetGenerator{
+				Values: []string{"admin", "regular"},
+				Given: &SetGiven{
+					Column:  "abc",
+					Weights: map[string][]int{"employee": {1, 0}},
+				},
+			},
+			expErr: `column "abc" must be generated before set column "user_type"`,
+		},
+		{
+			name: "missing weights",
+			generator: SetGenerator{
+				Values: []string{"admin", "regular"},
+				Given:  &SetGiven{Column: "person_type"},
+			},
+			expErr: "given must have 'weights'",
+		},
+		{
+			name: "mismatched weights",
+			generator: SetGenerator{
+				Values: []string{"admin", "regular"},
+				Given: &SetGiven{
+					Column:  "person_type",
+					Weights: map[string][]int{"employee": {1}},
+				},
+			},
+			expErr: `making weighted items collection for "employee": set values and weights need to be the same`,
+		},
+		{
+			name: "zero weights",
+			generator: SetGenerator{
+				Values: []string{"admin", "regular"},
+				Given: &SetGiven{
+					Column:  "person_type",
+					Weights: map[string][]int{"employee": {0, 0}},
+				},
+			},
+			expErr: `weights for "employee" must add up to more than 0`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			table := model.Table{
+				Name:  "person",
+				Count: 3,
+			}
+
+			files := map[string]model.CSVFile{
+				"person": {
+					Name:   "person",
+					Header: []string{"person_type"},
+					Lines:  [][]string{{"employee", "customer", "employee"}},
+				},
+			}
+
+			err := c.generator.Generate(table, model.Column{Name: "user_type"}, files)
+			if c.expErr != "" {
+				assert.EqualError(t, err, c.expErr)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, c.exp, files["person"].Lines[1])
+		})
+	}
+}
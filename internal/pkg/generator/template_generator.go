@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/codingconcepts/dg/internal/pkg/model"
+)
+
+// TemplateGenerator provides additional context to a template column.
+type TemplateGenerator struct {
+	Template string `yaml:"template,omitempty"`
+}
+
+// templateData is passed to a template for each row of a table.
+type templateData struct {
+	Row   map[string]string
+	Index int
+	Table string
+}
+
+// Generate executes a template for each row of a table, giving it access to
+// the values of the columns generated before it.
+func (g TemplateGenerator) Generate(t model.Table, c model.Column, files map[string]model.CSVFile) error {
+	if g.Template == "" {
+		return fmt.Errorf("template must have a 'template'")
+	}
+
+	tmpl, err := template.New(c.Name).
+		Funcs(templateFuncs()).
+		Option("missingkey=error").
+		Parse(g.Template)
+	if err != nil {
+		return fmt.Errorf("parsing template: %w", err)
+	}
+
+	file := files[t.Name]
+
+	count := file.RowCount()
+	if count == 0 {
+		count = t.Count
+	}
+
+	line := make([]string, count)
+	var sb strings.Builder
+
+	for i := 0; i < count; i++ {
+		data := templateData{
+			Row:   make(map[string]string, len(file.Header)),
+			Index: i,
+			Table: t.Name,
+		}
+
+		for j, name := range file.Header {
+			if i < len(file.Lines[j]) {
+				data.Row[name] = file.Lines[j][i]
+			}
+		}
+
+		sb.Reset()
+		if err = tmpl.Execute(&sb, data); err != nil {
+			return fmt.Errorf("executing template for row %d: %w", i+1, err)
+		}
+		line[i] = sb.String()
+	}
+
+	AddTable(t, c.Name, line, files)
+	return nil
+}
+
+// templateFuncs returns the functions available to templates, which include
+// a function for each placeholder (e.g. {{first_name}} for ${first_name}).
+func templateFuncs() template.FuncMap {
+	funcs := template.FuncMap{
+		"lower": func(v any) string { return strings.ToLower(fmt.Sprint(v)) },
+		"upper": func(v any) string { return strings.ToUpper(fmt.Sprint(v)) },
+		"title": func(v any) string { return title(fmt.Sprint(v)) },
+		"trim":  func(v any) string { return strings.TrimSpace(fmt.Sprint(v)) },
+		"replace": func(v any, from, to string) string {
+			return strings.ReplaceAll(fmt.Sprint(v), from, to)
+		},
+	}
+
+	for placeholder, f := range replacements {
+		name := strings.TrimSuffix(strings.TrimPrefix(placeholder, "${"), "}")
+		funcs[name] = f
+	}
+
+	return funcs
+}
+
+// title upper-cases the first letter of each word in a string.
+func title(s string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		if i == 0 || unicode.IsSpace(runes[i-1]) {
+			runes[i] = unicode.ToUpper(r)
+		}
+	}
+
+	return string(runes)
+}
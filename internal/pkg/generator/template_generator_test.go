@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateTemplateColumn(t *testing.T) {
+	file := model.CSVFile{
+		Name:   "person",
+		Header: []string{"first_name", "last_name"},
+		Lines: [][]string{
+			{"Alice", "bob"},
+			{"Smith", "van der berg"},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		template string
+		exp      []string
+		expMatch *regexp.Regexp
+		expErr   string
+	}{
+		{
+			name:     "row values",
+			template: "{{lower .Row.first_name}}.{{replace .Row.last_name \" \" \"\"}}",
+			exp:      []string{"alice.Smith", "bob.vanderberg"},
+		},
+		{
+			name:     "index and table",
+			template: "{{.Table}}-{{.Index}}",
+			exp:      []string{"person-0", "person-1"},
+		},
+		{
+			name:     "string functions",
+			template: "{{upper .Row.first_name}} {{title .Row.last_name}}",
+			exp:      []string{"ALICE Smith", "BOB Van Der Berg"},
+		},
+		{
+			name:     "placeholder functions",
+			template: "{{lower .Row.first_name}}@{{domain_name}}",
+			expMatch: regexp.MustCompile(`^(alice|bob)@\S+\.\S+$`),
+		},
+		{
+			name:     "missing column",
+			template: "{{.Row.email}}",
+			expErr:   `executing template for row 1: template: email:1:6: executing "email" at <.Row.email>: map has no entry for key "email"`,
+		},
+		{
+			name:     "invalid template",
+			template: "{{.Row.first_name",
+			expErr:   `parsing template: template: email:1: unclosed action`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			files := map[string]model.CSVFile{
+				file.Name: file,
+			}
+
+			g := TemplateGenerator{Template: c.template}
+
+			err := g.Generate(model.Table{Name: file.Name}, model.Column{Name: "email"}, files)
+			if c.expErr != "" {
+				assert.EqualError(t, err, c.expErr)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			act := files[file.Name].Lines[len(file.Header)]
+			if c.expMatch != nil {
+				for _, v := range act {
+					assert.Regexp(t, c.expMatch, v)
+				}
+				return
+			}
+			assert.Equal(t, c.exp, act)
+		})
+	}
+}
+
+func TestGenerateTemplateColumnWithoutColumns(t *testing.T) {
+	files := map[string]model.CSVFile{}
+
+	g := TemplateGenerator{Template: "row {{.Index}}"}
+	table := model.Table{Name: "table", Count: 2}
+
+	assert.NoError(t, g.Generate(table, model.Column{Name: "col"}, files))
+	assert.Equal(t, [][]string{{"row 0", "row 1"}}, files["table"].Lines)
+}
@@ -0,0 +1,183 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/codingconcepts/dg/internal/pkg/random"
+	"github.com/samber/lo"
+)
+
+// maxTimeseriesAttempts is the number of candidate timestamps that will be
+// rejected in a row before giving up on finding one with a non-zero weight.
+const maxTimeseriesAttempts = 100000
+
+// TimeseriesGenerator provides additional context to a timeseries column.
+type TimeseriesGenerator struct {
+	From        string `yaml:"from,omitempty"`
+	To          string `yaml:"to,omitempty"`
+	Format      string `yaml:"format,omitempty"`
+	InputFormat string `yaml:"input_format,omitempty"`
+	Timezone    string `yaml:"timezone,omitempty"`
+
+	// HourWeights weights each hour of the day, from midnight.
+	HourWeights []float64 `yaml:"hour_weights,omitempty"`
+
+	// WeekdayWeights weights each day of the week, from Monday.
+	WeekdayWeights []float64 `yaml:"weekday_weights,omitempty"`
+
+	// Trend is either "linear" or "exponential", and Growth is how many times
+	// more likely timestamps are at the end of the range than the start.
+	Trend  string  `yaml:"trend,omitempty"`
+	Growth float64 `yaml:"growth,omitempty"`
+
+	Sort bool `yaml:"sort,omitempty"`
+}
+
+// Generate timestamps between a from and to date, whose density follows the
+// hour of day and day of week weights, and trend of the generator.
+func (g TimeseriesGenerator) Generate(t model.Table, c model.Column, files map[string]model.CSVFile) error {
+	if err := g.validate(); err != nil {
+		return err
+	}
+
+	loc := time.UTC
+	if g.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(g.Timezone); err != nil {
+			return fmt.Errorf("loading timezone: %w", err)
+		}
+	}
+
+	inputFormat := lo.Ternary(g.InputFormat == "", time.RFC3339, g.InputFormat)
+	outputFormat := lo.Ternary(g.Format == "", time.RFC3339, g.Format)
+
+	from, err := time.ParseInLocation(inputFormat, g.From, loc)
+	if err != nil {
+		return fmt.Errorf("parsing from date: %w", err)
+	}
+
+	to, err := time.ParseInLocation(inputFormat, g.To, loc)
+	if err != nil {
+		return fmt.Errorf("parsing to date: %w", err)
+	}
+
+	if !to.After(from) {
+		return fmt.Errorf("to date must be after from date")
+	}
+
+	count := files[t.Name].RowCount()
+	if count == 0 {
+		count = t.Count
+	}
+
+	timestamps := make([]time.Time, count)
+	for i := range timestamps {
+		if timestamps[i], err = g.sample(from, to, loc); err != nil {
+			return err
+		}
+	}
+
+	if g.Sort {
+		sort.Slice(timestamps, func(i, j int) bool {
+			return timestamps[i].Before(timestamps[j])
+		})
+	}
+
+	line := lo.Map(timestamps, func(ts time.Time, _ int) string {
+		return ts.Format(outputFormat)
+	})
+
+	AddTable(t, c.Name, line, files)
+	return nil
+}
+
+func (g TimeseriesGenerator) validate() error {
+	if g.From == "" || g.To == "" {
+		return fmt.Errorf("timeseries must have a 'from' and a 'to'")
+	}
+
+	if len(g.HourWeights) > 0 && len(g.HourWeights) != 24 {
+		return fmt.Errorf("hour_weights must have 24 values but has %d", len(g.HourWeights))
+	}
+
+	if len(g.WeekdayWeights) > 0 && len(g.WeekdayWeights) != 7 {
+		return fmt.Errorf("weekday_weights must have 7 values but has %d", len(g.WeekdayWeights))
+	}
+
+	negative := func(w float64) bool { return w < 0 }
+	if lo.SomeBy(g.HourWeights, negative) || lo.SomeBy(g.WeekdayWeights, negative) {
+		return fmt.Errorf("weights must be 0 or greater")
+	}
+
+	switch g.Trend {
+	case "":
+	case "linear", "exponential":
+		if g.Growth <= 0 {
+			return fmt.Errorf("%s trend requires a 'growth' greater than 0", g.Trend)
+		}
+	default:
+		return fmt.Errorf("%q is not a valid trend", g.Trend)
+	}
+
+	return nil
+}
+
+// sample picks timestamps uniformly between from and to, accepting each with
+// a probability proportional to its weight, until one is accepted.
+func (g TimeseriesGenerator) sample(from, to time.Time, loc *time.Location) (time.Time, error) {
+	span := to.Sub(from)
+	maxWeight := maxOf(g.HourWeights) * maxOf(g.WeekdayWeights) * math.Max(g.trend(0), g.trend(1))
+
+	if maxWeight > 0 {
+		for i := 0; i < maxTimeseriesAttempts; i++ {
+			x := random.Float64()
+			ts := from.Add(time.Duration(x * float64(span))).In(loc)
+
+			if random.Float64()*maxWeight < g.weight(ts, x) {
+				return ts, nil
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("no timestamps between %q and %q have a weight greater than 0", g.From, g.To)
+}
+
+// weight returns the relative likelihood of a timestamp, x of the way
+// through the range.
+func (g TimeseriesGenerator) weight(ts time.Time, x float64) float64 {
+	w := g.trend(x)
+
+	if len(g.HourWeights) > 0 {
+		w *= g.HourWeights[ts.Hour()]
+	}
+
+	// Go's weekdays start on Sunday.
+	if len(g.WeekdayWeights) > 0 {
+		w *= g.WeekdayWeights[(ts.Weekday()+6)%7]
+	}
+
+	return w
+}
+
+func (g TimeseriesGenerator) trend(x float64) float64 {
+	switch g.Trend {
+	case "linear":
+		return 1 + (g.Growth-1)*x
+	case "exponential":
+		return math.Pow(g.Growth, x)
+	default:
+		return 1
+	}
+}
+
+// maxOf returns the largest of a set of weights, or 1 if there aren't any.
+func maxOf(weights []float64) float64 {
+	if len(weights) == 0 {
+		return 1
+	}
+	return lo.Max(weights)
+}
@@ -0,0 +1,159 @@
+package generator
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateTimeseriesColumn(t *testing.T) {
+	cases := []struct {
+		name      string
+		generator TimeseriesGenerator
+		expShape  func(t *testing.T, values []time.Time)
+		expErr    string
+	}{
+		{
+			name: "within range",
+			generator: TimeseriesGenerator{
+				From: "2023-01-01T00:00:00Z",
+				To:   "2023-01-08T00:00:00Z",
+			},
+			expShape: func(t *testing.T, values []time.Time) {
+				for _, v := range values {
+					assert.False(t, v.Before(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)))
+					assert.True(t, v.Before(time.Date(2023, 1, 8, 0, 0, 0, 0, time.UTC)))
+				}
+			},
+		},
+		{
+			name: "hour weights",
+			generator: TimeseriesGenerator{
+				From:        "2023-01-01T00:00:00Z",
+				To:          "2023-01-08T00:00:00Z",
+				HourWeights: append(make([]float64, 23), 1),
+			},
+			expShape: func(t *testing.T, values []time.Time) {
+				for _, v := range values {
+					assert.Equal(t, 23, v.Hour())
+				}
+			},
+		},
+		{
+			name: "weekday weights",
+			generator: TimeseriesGenerator{
+				From:           "2023-01-02T00:00:00Z",
+				To:             "2023-01-30T00:00:00Z",
+				WeekdayWeights: []float64{4, 4, 4, 4, 4, 1, 0},
+			},
+			expShape: func(t *testing.T, values []time.Time) {
+				counts := lo.CountValuesBy(values, func(v time.Time) time.Weekday { return v.Weekday() })
+				assert.Zero(t, counts[time.Sunday])
+				assert.Greater(t, counts[time.Monday], counts[time.Saturday])
+			},
+		},
+		{
+			name: "timezone",
+			generator: TimeseriesGenerator{
+				From:        "2023-01-01T00:00:00Z",
+				To:          "2023-01-08T00:00:00Z",
+				Timezone:    "Asia/Tokyo",
+				HourWeights: append([]float64{1}, make([]float64, 23)...),
+			},
+			expShape: func(t *testing.T, values []time.Time) {
+				for _, v := range values {
+					assert.Equal(t, 15, v.UTC().Hour())
+				}
+			},
+		},
+		{
+			name: "linear trend",
+			generator: TimeseriesGenerator{
+				From:   "2023-01-01T00:00:00Z",
+				To:     "2023-01-03T00:00:00Z",
+				Trend:  "linear",
+				Growth: 4,
+			},
+			expShape: func(t *testing.T, values []time.Time) {
+				mid := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+				first := lo.CountBy(values, func(v time.Time) bool { return v.Before(mid) })
+
+				// 1-2.5 vs 2.5-4 gives 7/20ths of timestamps in the first half.
+				assert.InDelta(t, 0.35, float64(first)/float64(len(values)), 0.05)
+			},
+		},
+		{
+			name: "exponential trend sorted",
+			generator: TimeseriesGenerator{
+				From:   "2023-01-01T00:00:00Z",
+				To:     "2023-01-03T00:00:00Z",
+				Trend:  "exponential",
+				Growth: 10,
+				Sort:   true,
+			},
+			expShape: func(t *testing.T, values []time.Time) {
+				assert.True(t, sort.SliceIsSorted(values, func(i, j int) bool {
+					return values[i].Before(values[j])
+				}))
+			},
+		},
+		{
+			name:      "missing to",
+			generator: TimeseriesGenerator{From: "2023-01-01T00:00:00Z"},
+			expErr:    "timeseries must have a 'from' and a 'to'",
+		},
+		{
+			name: "invalid hour weights",
+			generator: TimeseriesGenerator{
+				From:        "2023-01-01T00:00:00Z",
+				To:          "2023-01-08T00:00:00Z",
+				HourWeights: []float64{1, 2},
+			},
+			expErr: "hour_weights must have 24 values but has 2",
+		},
+		{
+			name: "invalid trend",
+			generator: TimeseriesGenerator{
+				From:  "2023-01-01T00:00:00Z",
+				To:    "2023-01-08T00:00:00Z",
+				Trend: "abc",
+			},
+			expErr: `"abc" is not a valid trend`,
+		},
+		{
+			name: "no weighted timestamps",
+			generator: TimeseriesGenerator{
+				From:           "2023-01-07T00:00:00Z",
+				To:             "2023-01-08T00:00:00Z",
+				WeekdayWeights: []float64{1, 1, 1, 1, 1, 0, 0},
+			},
+			expErr: `no timestamps between "2023-01-07T00:00:00Z" and "2023-01-08T00:00:00Z" have a weight greater than 0`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			table := model.Table{Name: "table", Count: 2000}
+			files := map[string]model.CSVFile{}
+
+			err := c.generator.Generate(table, model.Column{Name: "col"}, files)
+			if c.expErr != "" {
+				assert.Equal(t, c.expErr, err.Error())
+				return
+			}
+			assert.Nil(t, err)
+
+			values := lo.Map(files["table"].Lines[0], func(v string, _ int) time.Time {
+				ts, err := time.Parse(time.RFC3339, v)
+				assert.Nil(t, err)
+				return ts
+			})
+			assert.Len(t, values, 2000)
+			c.expShape(t, values)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/codingconcepts/dg/internal/pkg/random"
+	"github.com/samber/lo"
+)
+
+var formatVerbRegex = regexp.MustCompile(`%[-+# 0]*\d*(?:\.\d+)?([a-zA-Z])`)
+
+// Transform applies a column's transform to the values generated for it.
+// Values are trimmed, cased, formatted, given their prefix and suffix, padded,
+// and truncated in that order, after which empty values are given the default
+// value, and null_percentage of values are replaced with nulls.
+func Transform(t model.Table, c model.Column, files map[string]model.CSVFile) error {
+	tr := c.Transform
+	if tr == nil {
+		return nil
+	}
+
+	if err := validateTransform(*tr); err != nil {
+		return err
+	}
+
+	file := files[t.Name]
+	i := lo.LastIndexOf(file.Header, c.Name)
+	if i == -1 {
+		return fmt.Errorf("missing column %q", c.Name)
+	}
+
+	line := file.Lines[i]
+	for row, v := range line {
+		v, err := transformedValue(*tr, v)
+		if err != nil {
+			return fmt.Errorf("transforming row %d: %w", row+1, err)
+		}
+
+		if tr.NullPercentage > 0 && random.Intn(100) < tr.NullPercentage {
+			v = ""
+		}
+
+		line[row] = v
+	}
+
+	return nil
+}
+
+func validateTransform(tr model.Transform) error {
+	if tr.NullPercentage < 0 || tr.NullPercentage > 100 {
+		return fmt.Errorf("null_percentage must be between 0 and 100")
+	}
+
+	if tr.MaxLength < 0 {
+		return fmt.Errorf("max_length must be 0 or greater")
+	}
+
+	switch tr.Case {
+	case "", "upper", "lower", "title":
+	default:
+		return fmt.Errorf("%q is not a valid case", tr.Case)
+	}
+
+	if tr.Pad != nil {
+		switch tr.Pad.Side {
+		case "", "left", "right":
+		default:
+			return fmt.Errorf("%q is not a valid pad side", tr.Pad.Side)
+		}
+
+		if utf8.RuneCountInString(tr.Pad.Char) > 1 {
+			return fmt.Errorf("pad char must be a single character")
+		}
+	}
+
+	return nil
+}
+
+// transformedValue returns the value a transform turns a value into, before
+// any nulls are applied.
+func transformedValue(tr model.Transform, v string) (string, error) {
+	if v != "" {
+		var err error
+		if v, err = transformValue(tr, v); err != nil {
+			return "", err
+		}
+	}
+
+	return lo.Ternary(v == "", tr.Default, v), nil
+}
+
+func transformValue(tr model.Transform, v string) (string, error) {
+	if tr.Trim {
+		v = strings.TrimSpace(v)
+	}
+
+	switch tr.Case {
+	case "upper":
+		v = strings.ToUpper(v)
+	case "lower":
+		v = strings.ToLower(v)
+	case "title":
+		v = title(v)
+	}
+
+	if tr.Format != "" {
+		var err error
+		if v, err = formatString(tr.Format, v); err != nil {
+			return "", err
+		}
+	}
+
+	v = tr.Prefix + v + tr.Suffix
+
+	if tr.Pad != nil {
+		v = pad(*tr.Pad, v)
+	}
+
+	if tr.MaxLength > 0 && utf8.RuneCountInString(v) > tr.MaxLength {
+		v = string([]rune(v)[:tr.MaxLength])
+	}
+
+	return v, nil
+}
+
+// formatString formats a value as the type expected by the first verb in a
+// format string (e.g. as an integer for "%05d").
+func formatString(format, v string) (string, error) {
+	var verb string
+	if m := formatVerbRegex.FindStringSubmatch(format); m != nil {
+		verb = m[1]
+	}
+
+	switch verb {
+	case "d", "b", "o", "x", "X", "c":
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return fmt.Sprintf(format, i), nil
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return "", fmt.Errorf("formatting %q with %q: value isn't a number", v, format)
+		}
+		return fmt.Sprintf(format, int64(f)), nil
+
+	case "e", "E", "f", "F", "g", "G":
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return "", fmt.Errorf("formatting %q with %q: value isn't a number", v, format)
+		}
+		return fmt.Sprintf(format, f), nil
+
+	case "t":
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return "", fmt.Errorf("formatting %q with %q: value isn't a boolean", v, format)
+		}
+		return fmt.Sprintf(format, b), nil
+
+	default:
+		return fmt.Sprintf(format, v), nil
+	}
+}
+
+func pad(p model.Pad, v string) string {
+	n := p.Length - utf8.RuneCountInString(v)
+	if n <= 0 {
+		return v
+	}
+
+	padding := strings.Repeat(lo.Ternary(p.Char == "", " ", p.Char), n)
+	if p.Side == "right" {
+		return v + padding
+	}
+	return padding + v
+}
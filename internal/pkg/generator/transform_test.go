@@ -0,0 +1,147 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransform(t *testing.T) {
+	cases := []struct {
+		name      string
+		values    []string
+		transform *model.Transform
+		exp       []string
+		expErr    string
+	}{
+		{
+			name:   "no transform",
+			values: []string{"a", ""},
+			exp:    []string{"a", ""},
+		},
+		{
+			name:      "format integers",
+			values:    []string{"1", "23", "4.7"},
+			transform: &model.Transform{Format: "%04d"},
+			exp:       []string{"0001", "0023", "0004"},
+		},
+		{
+			name:      "format floats",
+			values:    []string{"1", "2.345"},
+			transform: &model.Transform{Format: "%.2f"},
+			exp:       []string{"1.00", "2.35"},
+		},
+		{
+			name:      "format strings",
+			values:    []string{"a"},
+			transform: &model.Transform{Format: "[%s]"},
+			exp:       []string{"[a]"},
+		},
+		{
+			name:      "format non-number",
+			values:    []string{"a"},
+			transform: &model.Transform{Format: "%d"},
+			expErr:    `transforming row 1: formatting "a" with "%d": value isn't a number`,
+		},
+		{
+			name:      "prefix and suffix",
+			values:    []string{"1", ""},
+			transform: &model.Transform{Prefix: "P-", Suffix: "-X"},
+			exp:       []string{"P-1-X", ""},
+		},
+		{
+			name:      "case",
+			values:    []string{"hello world"},
+			transform: &model.Transform{Case: "title"},
+			exp:       []string{"Hello World"},
+		},
+		{
+			name:      "trim and upper",
+			values:    []string{"  abc "},
+			transform: &model.Transform{Trim: true, Case: "upper"},
+			exp:       []string{"ABC"},
+		},
+		{
+			name:      "pad left",
+			values:    []string{"7", "12345"},
+			transform: &model.Transform{Pad: &model.Pad{Length: 4, Char: "0"}},
+			exp:       []string{"0007", "12345"},
+		},
+		{
+			name:      "pad right",
+			values:    []string{"ab"},
+			transform: &model.Transform{Pad: &model.Pad{Length: 4, Side: "right"}},
+			exp:       []string{"ab  "},
+		},
+		{
+			name:      "max length",
+			values:    []string{"héllo", "hi"},
+			transform: &model.Transform{MaxLength: 3},
+			exp:       []string{"hél", "hi"},
+		},
+		{
+			name:      "default",
+			values:    []string{"a", ""},
+			transform: &model.Transform{Default: "none", Case: "upper"},
+			exp:       []string{"A", "none"},
+		},
+		{
+			name:      "all nulls",
+			values:    []string{"a", "b"},
+			transform: &model.Transform{NullPercentage: 100, Default: "none"},
+			exp:       []string{"", ""},
+		},
+		{
+			name:      "invalid case",
+			values:    []string{"a"},
+			transform: &model.Transform{Case: "abc"},
+			expErr:    `"abc" is not a valid case`,
+		},
+		{
+			name:      "invalid pad side",
+			values:    []string{"a"},
+			transform: &model.Transform{Pad: &model.Pad{Side: "abc"}},
+			expErr:    `"abc" is not a valid pad side`,
+		},
+		{
+			name:      "invalid null percentage",
+			values:    []string{"a"},
+			transform: &model.Transform{NullPercentage: 101},
+			expErr:    "null_percentage must be between 0 and 100",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			table := model.Table{Name: "table"}
+			column := model.Column{Name: "col", Transform: c.transform}
+
+			files := map[string]model.CSVFile{}
+			AddTable(table, column.Name, append([]string{}, c.values...), files)
+
+			err := Transform(table, column, files)
+			if c.expErr != "" {
+				assert.EqualError(t, err, c.expErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, c.exp, files["table"].Lines[0])
+		})
+	}
+}
+
+func TestTransformNullPercentage(t *testing.T) {
+	table := model.Table{Name: "table"}
+	column := model.Column{Name: "col", Transform: &model.Transform{NullPercentage: 30}}
+
+	files := map[string]model.CSVFile{}
+	AddTable(table, column.Name, lo.Map(lo.Range(10000), func(_, _ int) string { return "a" }), files)
+
+	assert.NoError(t, Transform(table, column, files))
+
+	nulls := lo.Count(files["table"].Lines[0], "")
+	assert.InDelta(t, 3000, nulls, 300)
+}
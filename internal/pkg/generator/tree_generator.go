@@ -0,0 +1,154 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/codingconcepts/dg/internal/pkg/random"
+	"github.com/samber/lo"
+)
+
+// TreeGenerator provides additional context to a tree column.
+type TreeGenerator struct {
+	// Column is the column of the same table that parents are referenced by.
+	Column string `yaml:"column,omitempty"`
+
+	// MaxDepth is the maximum number of levels in a tree (unlimited if 0).
+	MaxDepth int `yaml:"max_depth,omitempty"`
+
+	// RootPercentage is the percentage of rows without a parent (at least
+	// one row is always a root).
+	RootPercentage float64 `yaml:"root_percentage,omitempty"`
+
+	// Branching is the number of children each row has, which is either
+	// between a min and max, or sampled from a distribution.
+	Branching *DistGenerator `yaml:"branching,omitempty"`
+
+	DepthColumn   string `yaml:"depth_column,omitempty"`
+	PathColumn    string `yaml:"path_column,omitempty"`
+	PathSeparator string `yaml:"path_separator,omitempty"`
+}
+
+// Generate a parent column that references another column of the same
+// table, such that the rows form one or more trees. Parents always appear
+// before their children.
+func (g TreeGenerator) Generate(t model.Table, c model.Column, files map[string]model.CSVFile) error {
+	if g.Column == "" {
+		return fmt.Errorf("tree must have a 'column'")
+	}
+
+	if g.MaxDepth < 0 {
+		return fmt.Errorf("max_depth must be 0 or greater")
+	}
+
+	if g.RootPercentage < 0 || g.RootPercentage > 100 {
+		return fmt.Errorf("root_percentage must be between 0 and 100")
+	}
+
+	file := files[t.Name]
+	colIndex := lo.IndexOf(file.Header, g.Column)
+	if colIndex == -1 {
+		return fmt.Errorf("column %q must be generated before tree column %q", g.Column, c.Name)
+	}
+	keys := file.Lines[colIndex]
+
+	count := file.RowCount()
+	if count == 0 {
+		count = t.Count
+	}
+
+	if len(keys) < count {
+		return fmt.Errorf("column %q has %d values but the table has %d rows", g.Column, len(keys), count)
+	}
+
+	branching := DistGenerator{Min: lo.ToPtr(1.0), Max: lo.ToPtr(5.0)}
+	if g.Branching != nil {
+		branching = *g.Branching
+	}
+
+	children, err := branching.FanOut()
+	if err != nil {
+		return fmt.Errorf("parsing branching: %w", err)
+	}
+
+	parents, depths := g.build(count, children)
+
+	parentLine := make([]string, count)
+	depthLine := make([]string, count)
+	pathLine := make([]string, count)
+	separator := lo.Ternary(g.PathSeparator == "", "/", g.PathSeparator)
+
+	for i, p := range parents {
+		depthLine[i] = strconv.Itoa(depths[i])
+		if p == -1 {
+			pathLine[i] = keys[i]
+			continue
+		}
+
+		parentLine[i] = keys[p]
+		pathLine[i] = pathLine[p] + separator + keys[i]
+	}
+
+	AddTable(t, c.Name, parentLine, files)
+	if g.DepthColumn != "" {
+		AddTable(t, g.DepthColumn, depthLine, files)
+	}
+	if g.PathColumn != "" {
+		AddTable(t, g.PathColumn, pathLine, files)
+	}
+
+	return nil
+}
+
+// build returns the index of each row's parent (-1 for roots) and each row's
+// depth (1 for roots). Rows are assigned breadth-first, with each row taking
+// the next few unassigned rows as its children.
+func (g TreeGenerator) build(count int, children func() int) ([]int, []int) {
+	parents := make([]int, count)
+	depths := make([]int, count)
+
+	roots := lo.Clamp(int(math.Round(float64(count)*g.RootPercentage/100)), 1, count)
+	if count == 0 {
+		roots = 0
+	}
+
+	for i := 0; i < roots; i++ {
+		parents[i], depths[i] = -1, 1
+	}
+
+	canHaveChildren := func(i int) bool {
+		return g.MaxDepth == 0 || depths[i] < g.MaxDepth
+	}
+
+	next := roots
+	for i := 0; i < next && next < count; i++ {
+		if !canHaveChildren(i) {
+			continue
+		}
+
+		for n := children(); n > 0 && next < count; n-- {
+			parents[next], depths[next] = i, depths[i]+1
+			next++
+		}
+	}
+
+	// If the branching or max depth leave rows without a parent, give them
+	// random parents that can have children, or make them roots.
+	eligible := lo.Filter(lo.Range(next), func(i, _ int) bool {
+		return canHaveChildren(i)
+	})
+
+	for ; next < count; next++ {
+		if len(eligible) == 0 {
+			parents[next], depths[next] = -1, 1
+			continue
+		}
+
+		p := eligible[random.Intn(len(eligible))]
+		parents[next], depths[next] = p, depths[p]+1
+	}
+
+	return parents, depths
+}
@@ -0,0 +1,146 @@
+package generator
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateTreeColumn(t *testing.T) {
+	cases := []struct {
+		name      string
+		count     int
+		generator TreeGenerator
+		expShape  func(t *testing.T, file model.CSVFile)
+		expErr    string
+	}{
+		{
+			name:      "single root",
+			count:     100,
+			generator: TreeGenerator{DepthColumn: "depth", PathColumn: "path"},
+			expShape: func(t *testing.T, file model.CSVFile) {
+				assert.Equal(t, []string{"id", "parent_id", "depth", "path"}, file.Header)
+				assert.Equal(t, 1, lo.Count(file.Lines[1], ""))
+				assert.Equal(t, "", file.Lines[1][0])
+			},
+		},
+		{
+			name:      "root percentage",
+			count:     100,
+			generator: TreeGenerator{RootPercentage: 20},
+			expShape: func(t *testing.T, file model.CSVFile) {
+				assert.Equal(t, 20, lo.Count(file.Lines[1], ""))
+			},
+		},
+		{
+			name:      "max depth",
+			count:     100,
+			generator: TreeGenerator{MaxDepth: 2, RootPercentage: 5, DepthColumn: "depth"},
+			expShape: func(t *testing.T, file model.CSVFile) {
+				for _, d := range file.Lines[2] {
+					assert.Contains(t, []string{"1", "2"}, d)
+				}
+			},
+		},
+		{
+			name:      "fixed branching",
+			count:     15,
+			generator: TreeGenerator{Branching: &DistGenerator{Min: lo.ToPtr(2.0), Max: lo.ToPtr(2.0)}, DepthColumn: "depth"},
+			expShape: func(t *testing.T, file model.CSVFile) {
+				// A complete binary tree of 4 levels.
+				assert.Equal(t, map[string]int{"1": 1, "2": 2, "3": 4, "4": 8}, lo.CountValues(file.Lines[2]))
+			},
+		},
+		{
+			name:  "no branching",
+			count: 10,
+			generator: TreeGenerator{
+				Branching:   &DistGenerator{Min: lo.ToPtr(0.0), Max: lo.ToPtr(0.0)},
+				MaxDepth:    3,
+				DepthColumn: "depth",
+			},
+			expShape: func(t *testing.T, file model.CSVFile) {
+				// Rows are attached to random parents instead.
+				assert.Equal(t, 1, lo.Count(file.Lines[1], ""))
+			},
+		},
+		{
+			name:      "missing column",
+			count:     10,
+			generator: TreeGenerator{Column: "abc"},
+			expErr:    `column "abc" must be generated before tree column "parent_id"`,
+		},
+		{
+			name:      "invalid root percentage",
+			count:     10,
+			generator: TreeGenerator{RootPercentage: 101},
+			expErr:    "root_percentage must be between 0 and 100",
+		},
+		{
+			name:      "invalid branching",
+			count:     10,
+			generator: TreeGenerator{Branching: &DistGenerator{Type: "abc"}},
+			expErr:    `parsing branching: "abc" is not a valid distribution type`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			table := model.Table{Name: "category", Count: c.count}
+			files := map[string]model.CSVFile{}
+
+			ids := lo.Map(lo.Range(c.count), func(i, _ int) string { return strconv.Itoa(i + 1) })
+			AddTable(table, "id", ids, files)
+
+			if c.generator.Column == "" {
+				c.generator.Column = "id"
+			}
+
+			err := c.generator.Generate(table, model.Column{Name: "parent_id"}, files)
+			if c.expErr != "" {
+				assert.Equal(t, c.expErr, err.Error())
+				return
+			}
+			assert.Nil(t, err)
+
+			file := files["category"]
+			assertValidTree(t, file.Lines[0], file.Lines[1])
+			c.expShape(t, file)
+		})
+	}
+}
+
+func TestGenerateTreeColumnPath(t *testing.T) {
+	table := model.Table{Name: "category", Count: 7}
+	files := map[string]model.CSVFile{}
+	AddTable(table, "id", []string{"a", "b", "c", "d", "e", "f", "g"}, files)
+
+	g := TreeGenerator{
+		Column:        "id",
+		Branching:     &DistGenerator{Min: lo.ToPtr(2.0), Max: lo.ToPtr(2.0)},
+		DepthColumn:   "depth",
+		PathColumn:    "path",
+		PathSeparator: ".",
+	}
+	assert.Nil(t, g.Generate(table, model.Column{Name: "parent_id"}, files))
+
+	file := files["category"]
+	assert.Equal(t, []string{"", "a", "a", "b", "b", "c", "c"}, file.Lines[1])
+	assert.Equal(t, []string{"1", "2", "2", "3", "3", "3", "3"}, file.Lines[2])
+	assert.Equal(t, []string{"a", "a.b", "a.c", "a.b.d", "a.b.e", "a.c.f", "a.c.g"}, file.Lines[3])
+}
+
+// assertValidTree asserts that every parent appears before its children,
+// which means there can't be any cycles.
+func assertValidTree(t *testing.T, ids, parents []string) {
+	seen := map[string]bool{}
+	for i, p := range parents {
+		if p != "" {
+			assert.True(t, seen[p], "parent %q of %q not seen before it", p, ids[i])
+		}
+		seen[ids[i]] = true
+	}
+}
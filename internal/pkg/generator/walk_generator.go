@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/samber/lo"
+)
+
+// WalkGenerator provides additional context to a walk column.
+type WalkGenerator struct {
+	// Start is the first value of the walk (and of each group's walk).
+	Start float64 `yaml:"start,omitempty"`
+
+	// Step is the distribution that each row's change is sampled from (by
+	// default, a normal distribution with a mean of 0 and stddev of 1).
+	Step *DistGenerator `yaml:"step,omitempty"`
+
+	// Drift is added to every step, to make the walk trend up or down.
+	Drift float64 `yaml:"drift,omitempty"`
+
+	// Reversion is the fraction of the distance to Mean (by default, Start)
+	// that the walk moves back by on every step, from 0 (none) to 1.
+	Reversion float64  `yaml:"reversion,omitempty"`
+	Mean      *float64 `yaml:"mean,omitempty"`
+
+	Min *float64 `yaml:"min,omitempty"`
+	Max *float64 `yaml:"max,omitempty"`
+
+	// GroupBy are columns of the same table, where each combination of
+	// their values has its own walk (e.g. one walk per device).
+	GroupBy []string `yaml:"group_by,omitempty,flow"`
+
+	Precision *int   `yaml:"precision,omitempty"`
+	Format    string `yaml:"format,omitempty"`
+}
+
+// Generate values for a column where each row's value is the previous row's
+// value plus a random step, such that values wander like prices or sensor
+// readings.
+func (g WalkGenerator) Generate(t model.Table, c model.Column, files map[string]model.CSVFile) error {
+	if g.Reversion < 0 || g.Reversion > 1 {
+		return fmt.Errorf("reversion must be between 0 and 1")
+	}
+
+	if g.Min != nil && g.Max != nil && *g.Min > *g.Max {
+		return fmt.Errorf("max must be greater than or equal to min")
+	}
+
+	step := DistGenerator{Type: "normal", StdDev: 1}
+	if g.Step != nil {
+		step = *g.Step
+	}
+
+	sample, err := step.sampler()
+	if err != nil {
+		return fmt.Errorf("parsing step: %w", err)
+	}
+
+	file := files[t.Name]
+
+	count := file.RowCount()
+	if count == 0 {
+		count = t.Count
+	}
+
+	groups, err := groupColumns(t, c, "walk", g.GroupBy, files)
+	if err != nil {
+		return err
+	}
+
+	bounds := DistGenerator{Min: g.Min, Max: g.Max}
+	mean := lo.FromPtrOr(g.Mean, g.Start)
+	output := DistGenerator{Precision: g.Precision, Format: g.Format}
+
+	previous := map[string]float64{}
+	line := make([]string, count)
+	for i := range line {
+		group := groupKey(groups, i)
+
+		v, ok := previous[group]
+		if !ok {
+			v = g.Start
+		} else {
+			v += g.Drift + g.Reversion*(mean-v) + step.clamp(sample())
+		}
+
+		v = bounds.clamp(v)
+		previous[group] = v
+		line[i] = output.format(v)
+	}
+
+	AddTable(t, c.Name, line, files)
+	return nil
+}
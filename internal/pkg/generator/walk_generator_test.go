@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateWalkColumn(t *testing.T) {
+	constant := func(v float64) *DistGenerator {
+		return &DistGenerator{Type: "uniform", Min: lo.ToPtr(v), Max: lo.ToPtr(v)}
+	}
+
+	cases := []struct {
+		name      string
+		generator WalkGenerator
+		exp       []string
+		expShape  func(t *testing.T, line []string)
+		expErr    string
+	}{
+		{
+			name:      "steps",
+			generator: WalkGenerator{Start: 10, Step: constant(2)},
+			exp:       []string{"10", "12", "14", "16", "18", "20"},
+		},
+		{
+			name:      "drift",
+			generator: WalkGenerator{Start: 10, Step: constant(0), Drift: -1},
+			exp:       []string{"10", "9", "8", "7", "6", "5"},
+		},
+		{
+			name:      "bounds",
+			generator: WalkGenerator{Start: 10, Step: constant(5), Max: lo.ToPtr(20.0)},
+			exp:       []string{"10", "15", "20", "20", "20", "20"},
+		},
+		{
+			name:      "mean reversion",
+			generator: WalkGenerator{Start: 64, Step: constant(0), Mean: lo.ToPtr(0.0), Reversion: 0.5},
+			exp:       []string{"64", "32", "16", "8", "4", "2"},
+		},
+		{
+			name:      "group by",
+			generator: WalkGenerator{Start: 1, Step: constant(1), GroupBy: []string{"device"}},
+			exp:       []string{"1", "2", "3", "1", "2", "3"},
+		},
+		{
+			name:      "group by multiple columns",
+			generator: WalkGenerator{Start: 1, Step: constant(1), GroupBy: []string{"device", "sensor"}},
+			exp:       []string{"1", "1", "2", "1", "1", "2"},
+		},
+		{
+			name:      "precision",
+			generator: WalkGenerator{Start: 1, Step: constant(0.125), Precision: lo.ToPtr(2)},
+			exp:       []string{"1.00", "1.13", "1.25", "1.38", "1.50", "1.63"},
+		},
+		{
+			name:      "default step",
+			generator: WalkGenerator{Start: 100, Min: lo.ToPtr(99.0), Max: lo.ToPtr(101.0)},
+			expShape: func(t *testing.T, line []string) {
+				assert.Equal(t, "100", line[0])
+				for _, v := range line {
+					f, err := strconv.ParseFloat(v, 64)
+					assert.NoError(t, err)
+					assert.True(t, f >= 99 && f <= 101)
+				}
+			},
+		},
+		{
+			name:      "invalid reversion",
+			generator: WalkGenerator{Reversion: 2},
+			expErr:    "reversion must be between 0 and 1",
+		},
+		{
+			name:      "invalid bounds",
+			generator: WalkGenerator{Min: lo.ToPtr(2.0), Max: lo.ToPtr(1.0)},
+			expErr:    "max must be greater than or equal to min",
+		},
+		{
+			name:      "invalid step",
+			generator: WalkGenerator{Step: &DistGenerator{Type: "abc"}},
+			expErr:    `parsing step: "abc" is not a valid distribution type`,
+		},
+		{
+			name:      "missing group by column",
+			generator: WalkGenerator{GroupBy: []string{"device", "abc"}},
+			expErr:    `column "abc" must be generated before walk column "reading"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			table := model.Table{
+				Name:  "reading",
+				Count: 6,
+			}
+
+			files := map[string]model.CSVFile{
+				"reading": {
+					Name:   "reading",
+					Header: []string{"device", "sensor"},
+					Lines: [][]string{
+						{"a", "a", "a", "b", "b", "b"},
+						{"x", "y", "x", "x", "y", "y"},
+					},
+				},
+			}
+
+			err := c.generator.Generate(table, model.Column{Name: "reading"}, files)
+			if c.expErr != "" {
+				assert.EqualError(t, err, c.expErr)
+				return
+			}
+			assert.Nil(t, err)
+
+			line := files["reading"].Lines[2]
+			if c.expShape != nil {
+				c.expShape(t, line)
+				return
+			}
+			assert.Equal(t, c.exp, line)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package model
 import (
 	"fmt"
 	"io"
+	"strconv"
 
 	"gopkg.in/yaml.v3"
 )
@@ -16,9 +17,11 @@ type Config struct {
 // Table represents the instructions to create one CSV file.
 type Table struct {
 	Name          string   `yaml:"name"`
-	Count         int      `yaml:"count"`
+	Count         int      `yaml:"-"`
+	CountExpr     string   `yaml:"count"`
 	Suppress      bool     `yaml:"suppress"`
 	UniqueColumns []string `yaml:"unique_columns"`
+	UniqueMode    string   `yaml:"unique_mode"`
 	Columns       []Column `yaml:"columns"`
 }
 
@@ -28,6 +31,28 @@ type Column struct {
 	Type      string     `yaml:"type"`
 	Suppress  bool       `yaml:"suppress"`
 	Generator RawMessage `yaml:"processor"`
+	Transform *Transform `yaml:"transform"`
+}
+
+// Transform represents the post-processing applied to a column's values
+// once they've been generated, regardless of the column's type.
+type Transform struct {
+	NullPercentage int    `yaml:"null_percentage"`
+	Format         string `yaml:"format"`
+	Prefix         string `yaml:"prefix"`
+	Suffix         string `yaml:"suffix"`
+	Case           string `yaml:"case"`
+	Trim           bool   `yaml:"trim"`
+	Pad            *Pad   `yaml:"pad"`
+	MaxLength      int    `yaml:"max_length"`
+	Default        string `yaml:"default"`
+}
+
+// Pad represents the padding of values to a minimum length.
+type Pad struct {
+	Length int    `yaml:"length"`
+	Char   string `yaml:"char"`
+	Side   string `yaml:"side"`
 }
 
 // Input represents a data source provided by the user.
@@ -44,5 +69,22 @@ func LoadConfig(r io.Reader) (Config, error) {
 		return Config{}, fmt.Errorf("parsing file: %w", err)
 	}
 
+	// Resolve any literal counts now, leaving expressions to be evaluated
+	// once the tables they depend on have been generated.
+	for i, t := range c.Tables {
+		if t.CountExpr == "" {
+			continue
+		}
+
+		if n, err := strconv.Atoi(t.CountExpr); err == nil {
+			c.Tables[i].Count = n
+			continue
+		}
+
+		if _, err := t.CountDependencies(); err != nil {
+			return Config{}, fmt.Errorf("parsing count for %q: %w", t.Name, err)
+		}
+	}
+
 	return c, nil
 }
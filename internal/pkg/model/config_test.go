@@ -81,3 +81,33 @@ tables:
 
 	assert.Equal(t, expProcessor, actProcessor)
 }
+
+func TestLoadConfigCount(t *testing.T) {
+	y := `
+tables:
+  - name: customer
+    count: 100
+  - name: order
+    count: customer * 3
+`
+
+	config, err := LoadConfig(strings.NewReader(y))
+	assert.Nil(t, err)
+
+	assert.Equal(t, 100, config.Tables[0].Count)
+	assert.Equal(t, "100", config.Tables[0].CountExpr)
+
+	assert.Equal(t, 0, config.Tables[1].Count)
+	assert.Equal(t, "customer * 3", config.Tables[1].CountExpr)
+}
+
+func TestLoadConfigInvalidCount(t *testing.T) {
+	y := `
+tables:
+  - name: order
+    count: customer *
+`
+
+	_, err := LoadConfig(strings.NewReader(y))
+	assert.ErrorContains(t, err, `parsing count for "order"`)
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/parser"
+)
+
+// CountDependencies returns the names of the tables referenced by a table's
+// count expression (e.g. "customer * 3" depends on "customer").
+func (t Table) CountDependencies() ([]string, error) {
+	if t.CountExpr == "" {
+		return nil, nil
+	}
+
+	tree, err := parser.Parse(t.CountExpr)
+	if err != nil {
+		return nil, fmt.Errorf("parsing count expression: %w", err)
+	}
+
+	v := &identifierVisitor{identifiers: map[string]struct{}{}}
+	ast.Walk(&tree.Node, v)
+
+	deps := make([]string, 0, len(v.identifiers))
+	for id := range v.identifiers {
+		deps = append(deps, id)
+	}
+	sort.Strings(deps)
+
+	return deps, nil
+}
+
+// EvaluateCount returns the number of rows to generate for a table, evaluating
+// its count expression against the row counts of other tables.
+func (t Table) EvaluateCount(counts map[string]int) (int, error) {
+	if t.CountExpr == "" || t.Count != 0 {
+		return t.Count, nil
+	}
+
+	deps, err := t.CountDependencies()
+	if err != nil {
+		return 0, err
+	}
+
+	env := map[string]any{}
+	for _, dep := range deps {
+		count, ok := counts[dep]
+		if !ok {
+			return 0, fmt.Errorf("missing table %q for count expression", dep)
+		}
+		env[dep] = count
+	}
+
+	result, err := expr.Eval(t.CountExpr, env)
+	if err != nil {
+		return 0, fmt.Errorf("evaluating count expression: %w", err)
+	}
+
+	var count float64
+	switch v := result.(type) {
+	case int:
+		count = float64(v)
+	case float64:
+		count = v
+	default:
+		return 0, fmt.Errorf("count expression %q must evaluate to a number, got %T", t.CountExpr, result)
+	}
+
+	if math.IsNaN(count) || math.IsInf(count, 0) || count < 0 {
+		return 0, fmt.Errorf("count expression %q must evaluate to a finite number of 0 or more, got %v", t.CountExpr, count)
+	}
+
+	return int(count), nil
+}
+
+// RowCounts returns the number of rows in each of the given files.
+func RowCounts(files map[string]CSVFile) map[string]int {
+	counts := make(map[string]int, len(files))
+	for name, file := range files {
+		counts[name] = file.RowCount()
+	}
+
+	return counts
+}
+
+type identifierVisitor struct {
+	identifiers map[string]struct{}
+}
+
+func (v *identifierVisitor) Visit(node *ast.Node) {
+	if n, ok := (*node).(*ast.IdentifierNode); ok {
+		v.identifiers[n.Value] = struct{}{}
+	}
+}
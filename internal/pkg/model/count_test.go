@@ -0,0 +1,156 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountDependencies(t *testing.T) {
+	cases := []struct {
+		name      string
+		countExpr string
+		exp       []string
+		expErr    bool
+	}{
+		{
+			name: "no count",
+		},
+		{
+			name:      "literal count",
+			countExpr: "10",
+			exp:       []string{},
+		},
+		{
+			name:      "single table",
+			countExpr: "customer * 3",
+			exp:       []string{"customer"},
+		},
+		{
+			name:      "multiple tables with function",
+			countExpr: "round(product / 10) + customer",
+			exp:       []string{"customer", "product"},
+		},
+		{
+			name:      "invalid expression",
+			countExpr: "customer *",
+			expErr:    true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			table := Table{CountExpr: c.countExpr}
+
+			act, err := table.CountDependencies()
+			if c.expErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, c.exp, act)
+		})
+	}
+}
+
+func TestEvaluateCount(t *testing.T) {
+	cases := []struct {
+		name      string
+		count     int
+		countExpr string
+		counts    map[string]int
+		exp       int
+		expErr    string
+	}{
+		{
+			name:  "literal count",
+			count: 10,
+			exp:   10,
+		},
+		{
+			name:      "multiplied count",
+			countExpr: "customer * 3",
+			counts:    map[string]int{"customer": 100},
+			exp:       300,
+		},
+		{
+			name:      "rounded count",
+			countExpr: "round(product / 8)",
+			counts:    map[string]int{"product": 100},
+			exp:       13,
+		},
+		{
+			name:      "truncated count",
+			countExpr: "product / 8",
+			counts:    map[string]int{"product": 100},
+			exp:       12,
+		},
+		{
+			name:      "missing table",
+			countExpr: "customer * 3",
+			counts:    map[string]int{},
+			expErr:    `missing table "customer" for count expression`,
+		},
+		{
+			name:      "non-numeric result",
+			countExpr: "customer > 3",
+			counts:    map[string]int{"customer": 100},
+			expErr:    `count expression "customer > 3" must evaluate to a number, got bool`,
+		},
+		{
+			name:      "negative result",
+			countExpr: "customer - 10",
+			counts:    map[string]int{"customer": 5},
+			expErr:    `count expression "customer - 10" must evaluate to a finite number of 0 or more, got -5`,
+		},
+		{
+			name:      "negative fractional result",
+			countExpr: "customer / -2",
+			counts:    map[string]int{"customer": 5},
+			expErr:    `count expression "customer / -2" must evaluate to a finite number of 0 or more, got -2.5`,
+		},
+		{
+			name:      "nan result",
+			countExpr: "customer / customer",
+			counts:    map[string]int{"customer": 0},
+			expErr:    `count expression "customer / customer" must evaluate to a finite number of 0 or more, got NaN`,
+		},
+		{
+			name:      "infinite result",
+			countExpr: "customer / 0",
+			counts:    map[string]int{"customer": 5},
+			expErr:    `count expression "customer / 0" must evaluate to a finite number of 0 or more, got +Inf`,
+		},
+		{
+			name:      "negative infinite result",
+			countExpr: "-customer / 0",
+			counts:    map[string]int{"customer": 5},
+			expErr:    `count expression "-customer / 0" must evaluate to a finite number of 0 or more, got -Inf`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			table := Table{Count: c.count, CountExpr: c.countExpr}
+
+			act, err := table.EvaluateCount(c.counts)
+			if c.expErr != "" {
+				assert.EqualError(t, err, c.expErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, c.exp, act)
+		})
+	}
+}
+
+func TestRowCounts(t *testing.T) {
+	files := map[string]CSVFile{
+		"a": {Lines: [][]string{{"1", "2"}, {"1", "2", "3"}}},
+		"b": {},
+	}
+
+	assert.Equal(t, map[string]int{"a": 3, "b": 0}, RowCounts(files))
+}
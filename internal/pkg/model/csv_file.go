@@ -1,6 +1,8 @@
 package model
 
 import (
+	"strings"
+
 	"github.com/samber/lo"
 )
 
@@ -13,6 +15,19 @@ type CSVFile struct {
 	Output        bool
 }
 
+// RowCount returns the number of rows in the CSVFile, which is the length
+// of its longest column.
+func (c CSVFile) RowCount() int {
+	count := 0
+	for _, line := range c.Lines {
+		if len(line) > count {
+			count = len(line)
+		}
+	}
+
+	return count
+}
+
 // Unique removes any duplicates from the CSVFile's lines.
 func (c *CSVFile) Unique() [][]string {
 	uniqueColumnIndexes := uniqueIndexes(c.Header, c.UniqueColumns)
@@ -32,6 +47,34 @@ func (c *CSVFile) Unique() [][]string {
 	return uniqueLines
 }
 
+// DuplicateRows returns the indexes of the rows whose unique columns have the
+// same values as an earlier row.
+func (c CSVFile) DuplicateRows() []int {
+	uniqueColumnIndexes := uniqueIndexes(c.Header, c.UniqueColumns)
+
+	seen := map[string]struct{}{}
+	var duplicates []int
+
+	key := make([]string, len(uniqueColumnIndexes))
+	for row, rows := 0, c.RowCount(); row < rows; row++ {
+		for i, col := range uniqueColumnIndexes {
+			key[i] = ""
+			if row < len(c.Lines[col]) {
+				key[i] = c.Lines[col][row]
+			}
+		}
+
+		k := strings.Join(key, "\x00")
+		if _, ok := seen[k]; ok {
+			duplicates = append(duplicates, row)
+			continue
+		}
+		seen[k] = struct{}{}
+	}
+
+	return duplicates
+}
+
 func uniqueIndexes(header, uniqueColumns []string) []int {
 	indexes := []int{}
 
@@ -113,3 +113,43 @@ func TestUnique(t *testing.T) {
 		})
 	}
 }
+
+func TestDuplicateRows(t *testing.T) {
+	cases := []struct {
+		name          string
+		uniqueColumns []string
+		exp           []int
+	}{
+		{
+			name:          "single column",
+			uniqueColumns: []string{"a"},
+			exp:           []int{2, 3},
+		},
+		{
+			name:          "multiple columns",
+			uniqueColumns: []string{"a", "b"},
+			exp:           []int{3},
+		},
+		{
+			name:          "values aren't concatenated",
+			uniqueColumns: []string{"b", "c"},
+			exp:           nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			file := CSVFile{
+				Header:        []string{"a", "b", "c"},
+				UniqueColumns: c.uniqueColumns,
+				Lines: [][]string{
+					{"x", "y", "x", "x"},
+					{"1", "1", "12", "1"},
+					{"23", "2", "3", "4"},
+				},
+			}
+
+			assert.Equal(t, c.exp, file.DuplicateRows())
+		})
+	}
+}
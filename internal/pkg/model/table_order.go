@@ -0,0 +1,138 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// columnReference captures the fields used by processors that read from
+// other tables (e.g. ref, each, and match), including the processors
+// embedded in the branches of a case column.
+type columnReference struct {
+	Table       string `yaml:"table"`
+	SourceTable string `yaml:"source_table"`
+
+	When []branchReference `yaml:"when"`
+	Else *branchReference  `yaml:"else"`
+}
+
+type branchReference struct {
+	Processor columnReference `yaml:"processor"`
+}
+
+// tables returns the names of the tables a processor reads from.
+func (r columnReference) tables() []string {
+	names := []string{r.Table, r.SourceTable}
+
+	branches := r.When
+	if r.Else != nil {
+		branches = append(branches[:len(branches):len(branches)], *r.Else)
+	}
+
+	for _, b := range branches {
+		names = append(names, b.Processor.tables()...)
+	}
+
+	return names
+}
+
+// Dependencies returns the names of the tables that need to be generated
+// before this table can be, taken from its count expression and from any
+// columns that reference other tables.
+func (t Table) Dependencies() ([]string, error) {
+	deps, err := t.CountDependencies()
+	if err != nil {
+		return nil, fmt.Errorf("getting count dependencies: %w", err)
+	}
+
+	for _, c := range t.Columns {
+		if c.Generator.UnmarshalFunc == nil {
+			continue
+		}
+
+		var ref columnReference
+		if err := c.Generator.UnmarshalFunc(&ref); err != nil {
+			continue
+		}
+
+		for _, name := range ref.tables() {
+			if name != "" && name != t.Name {
+				deps = append(deps, name)
+			}
+		}
+	}
+
+	return lo.Uniq(deps), nil
+}
+
+// SortTables orders tables so that every table appears after the tables it
+// depends on. Tables without dependencies between them keep the order in
+// which they were configured. Dependencies that aren't tables (e.g. inputs)
+// are ignored, as they're available before any table is generated.
+func SortTables(tables []Table) ([]Table, error) {
+	names := lo.Map(tables, func(t Table, _ int) string {
+		return t.Name
+	})
+
+	deps := make([][]string, len(tables))
+	for i, t := range tables {
+		tableDeps, err := t.Dependencies()
+		if err != nil {
+			return nil, fmt.Errorf("getting dependencies for %q: %w", t.Name, err)
+		}
+		deps[i] = tableDeps
+	}
+
+	order, err := OrderByDependencies(names, deps)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Map(order, func(i int, _ int) Table {
+		return tables[i]
+	}), nil
+}
+
+// OrderByDependencies returns the indexes of the given names, ordered so that
+// each name appears after the names it depends on, where deps[i] holds the
+// dependencies of names[i]. Names without dependencies between them keep
+// their original order and dependencies not found in names are ignored.
+func OrderByDependencies(names []string, deps [][]string) ([]int, error) {
+	order := make([]int, 0, len(names))
+	added := make([]bool, len(names))
+	done := map[string]bool{}
+
+	for len(order) < len(names) {
+		progressed := false
+
+		for i, name := range names {
+			if added[i] {
+				continue
+			}
+
+			ready := lo.EveryBy(deps[i], func(d string) bool {
+				return done[d] || d == name || !lo.Contains(names, d)
+			})
+			if !ready {
+				continue
+			}
+
+			order = append(order, i)
+			added[i] = true
+			done[name] = true
+			progressed = true
+			break
+		}
+
+		if !progressed {
+			remaining := lo.Filter(names, func(_ string, i int) bool {
+				return !added[i]
+			})
+			return nil, fmt.Errorf("circular dependency between tables: %s", strings.Join(remaining, ", "))
+		}
+	}
+
+	return order, nil
+}
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortTables(t *testing.T) {
+	cases := []struct {
+		name   string
+		tables []Table
+		exp    []string
+		expErr string
+	}{
+		{
+			name: "no dependencies keeps config order",
+			tables: []Table{
+				{Name: "b"},
+				{Name: "a"},
+			},
+			exp: []string{"b", "a"},
+		},
+		{
+			name: "count dependency",
+			tables: []Table{
+				{Name: "order", CountExpr: "customer * 3"},
+				{Name: "customer", Count: 10},
+			},
+			exp: []string{"customer", "order"},
+		},
+		{
+			name: "column dependency",
+			tables: []Table{
+				{
+					Name: "pet",
+					Columns: []Column{
+						{
+							Name:      "person_id",
+							Type:      "ref",
+							Generator: ToRawMessage(t, map[string]any{"table": "person", "column": "id"}),
+						},
+					},
+				},
+				{Name: "person"},
+			},
+			exp: []string{"person", "pet"},
+		},
+		{
+			name: "case branch dependency",
+			tables: []Table{
+				{
+					Name: "pet",
+					Columns: []Column{
+						{
+							Name: "owner_id",
+							Type: "case",
+							Generator: ToRawMessage(t, map[string]any{
+								"when": []any{
+									map[string]any{
+										"column":    "kind",
+										"equals":    "dog",
+										"type":      "ref",
+										"processor": map[string]any{"table": "person", "column": "id"},
+									},
+								},
+								"else": map[string]any{
+									"type":      "ref",
+									"processor": map[string]any{"table": "shelter", "column": "id"},
+								},
+							}),
+						},
+					},
+				},
+				{Name: "shelter"},
+				{Name: "person"},
+			},
+			exp: []string{"shelter", "person", "pet"},
+		},
+		{
+			name: "input dependency ignored",
+			tables: []Table{
+				{
+					Name: "market",
+					Columns: []Column{
+						{
+							Name:      "code",
+							Type:      "match",
+							Generator: ToRawMessage(t, map[string]any{"source_table": "market_input"}),
+						},
+					},
+				},
+			},
+			exp: []string{"market"},
+		},
+		{
+			name: "circular dependency",
+			tables: []Table{
+				{Name: "a", CountExpr: "b"},
+				{Name: "b", CountExpr: "a"},
+			},
+			expErr: "circular dependency between tables: a, b",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			act, err := SortTables(c.tables)
+			if c.expErr != "" {
+				assert.EqualError(t, err, c.expErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, c.exp, lo.Map(act, func(t Table, _ int) string {
+				return t.Name
+			}))
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package random
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Float64 returns a pseudo-random number in [0.0,1.0).
+func Float64() float64 {
+	return rnd.Float64()
+}
+
+// NormFloat64 returns a normally distributed number with a mean of 0 and a
+// standard deviation of 1.
+func NormFloat64() float64 {
+	return rnd.NormFloat64()
+}
+
+// ExpFloat64 returns an exponentially distributed number with a rate of 1.
+func ExpFloat64() float64 {
+	return rnd.ExpFloat64()
+}
+
+// NewZipf returns a Zipf distributed number generator, which returns
+// numbers between 0 and imax. See rand.NewZipf for the meaning of s and v.
+func NewZipf(s, v float64, imax uint64) *rand.Zipf {
+	return rand.NewZipf(rnd, s, v, imax)
+}
+
+// Poisson returns a Poisson distributed number with a mean of lambda.
+func Poisson(lambda float64) int {
+	// Knuth's algorithm takes a number of steps proportional to lambda, so
+	// approximate larger lambdas with a normal distribution.
+	if lambda > 30 {
+		return int(math.Max(0, math.Round(lambda+math.Sqrt(lambda)*NormFloat64())))
+	}
+
+	l := math.Exp(-lambda)
+	k, p := 0, 1.0
+	for {
+		p *= Float64()
+		if p <= l {
+			return k
+		}
+		k++
+	}
+}
+
+// Gamma returns a gamma distributed number with the given shape and a scale
+// of 1, using the method described by Marsaglia and Tsang.
+func Gamma(shape float64) float64 {
+	if shape < 1 {
+		return Gamma(shape+1) * math.Pow(Float64(), 1/shape)
+	}
+
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+
+	for {
+		x := NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+
+		v = v * v * v
+		u := Float64()
+		if u < 1-0.0331*x*x*x*x || math.Log(u) < 0.5*x*x+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
+
+// Beta returns a beta distributed number between 0 and 1.
+func Beta(alpha, beta float64) float64 {
+	x := Gamma(alpha)
+	y := Gamma(beta)
+
+	return x / (x + y)
+}
@@ -0,0 +1,63 @@
+package random
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeed(t *testing.T) {
+	sample := func() []float64 {
+		return []float64{float64(Intn(100)), Float64(), NormFloat64(), ExpFloat64(), float64(Poisson(4)), Beta(2, 5)}
+	}
+
+	Seed(42)
+	first := sample()
+
+	Seed(42)
+	second := sample()
+
+	Seed(43)
+	third := sample()
+
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first, third)
+}
+
+func TestDistributions(t *testing.T) {
+	Seed(1)
+
+	cases := []struct {
+		name    string
+		sample  func() float64
+		expMean float64
+		min     float64
+		max     float64
+	}{
+		{name: "float64", sample: Float64, expMean: 0.5, min: 0, max: 1},
+		{name: "norm", sample: NormFloat64, expMean: 0, min: -10, max: 10},
+		{name: "exp", sample: ExpFloat64, expMean: 1, min: 0, max: 100},
+		{name: "poisson small", sample: func() float64 { return float64(Poisson(3)) }, expMean: 3, min: 0, max: 100},
+		{name: "poisson large", sample: func() float64 { return float64(Poisson(100)) }, expMean: 100, min: 0, max: 1000},
+		{name: "gamma", sample: func() float64 { return Gamma(2) }, expMean: 2, min: 0, max: 100},
+		{name: "gamma small shape", sample: func() float64 { return Gamma(0.5) }, expMean: 0.5, min: 0, max: 100},
+		{name: "beta", sample: func() float64 { return Beta(2, 6) }, expMean: 0.25, min: 0, max: 1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			const n = 20000
+
+			var total float64
+			for i := 0; i < n; i++ {
+				v := c.sample()
+				assert.GreaterOrEqual(t, v, c.min)
+				assert.LessOrEqual(t, v, c.max)
+				total += v
+			}
+
+			assert.InDelta(t, c.expMean, total/n, 0.05*math.Max(c.expMean, 1))
+		})
+	}
+}
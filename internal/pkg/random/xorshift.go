@@ -1,9 +1,13 @@
 package random
 
-import "time"
+import (
+	"math/rand"
+	"time"
+)
 
 var (
-	r = newSplitMix64(time.Now().UnixNano())
+	r   = newSplitMix64(time.Now().UnixNano())
+	rnd = rand.New(r)
 )
 
 type splitMix64 struct {
@@ -16,12 +20,38 @@ func newSplitMix64(seed int64) *splitMix64 {
 	}
 }
 
+// Seed resets the generator, so that the same sequence of values will be
+// returned for the same seed.
+func Seed(seed int64) {
+	rnd.Seed(seed)
+}
+
 // Intn returns a non-negative pseudo-random int.
 func Intn(n int) int {
 	return int(r.uint64()&(1<<63-1)) % n
 
 }
 
+// Int63 returns a non-negative pseudo-random int64.
+func Int63() int64 {
+	return r.Int63()
+}
+
+// Int63 implements rand.Source.
+func (x *splitMix64) Int63() int64 {
+	return int64(x.uint64() & (1<<63 - 1))
+}
+
+// Uint64 implements rand.Source64.
+func (x *splitMix64) Uint64() uint64 {
+	return x.uint64()
+}
+
+// Seed implements rand.Source.
+func (x *splitMix64) Seed(seed int64) {
+	x.s = uint64(seed)
+}
+
 func (x *splitMix64) uint64() uint64 {
 	x.s = x.s + uint64(0x9E3779B97F4A7C15)
 	z := x.s
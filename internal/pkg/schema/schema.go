@@ -0,0 +1,206 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/codingconcepts/dg/internal/pkg/generator"
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/samber/lo"
+)
+
+// processors maps each column type to the processor that configures it.
+var processors = map[string]any{
+	"case":       generator.CaseGenerator{},
+	"const":      generator.ConstGenerator{},
+	"dist":       generator.DistGenerator{},
+	"each":       generator.EachGenerator{},
+	"expr":       generator.ExprGenerator{},
+	"gen":        generator.GenGenerator{},
+	"inc":        generator.IncGenerator{},
+	"match":      generator.MatchGenerator{},
+	"range":      generator.RangeGenerator{},
+	"ref":        generator.RefGenerator{},
+	"set":        generator.SetGenerator{},
+	"template":   generator.TemplateGenerator{},
+	"timeseries": generator.TimeseriesGenerator{},
+	"tree":       generator.TreeGenerator{},
+	"walk":       generator.WalkGenerator{},
+}
+
+// sources maps each input type to the source that configures it.
+var sources = map[string]any{
+	"csv": model.SourceCSV{},
+}
+
+var rawMessageType = reflect.TypeOf(model.RawMessage{})
+
+// Generate returns a JSON Schema document that describes dg config files.
+func Generate() ([]byte, error) {
+	return json.MarshalIndent(Build(), "", "  ")
+}
+
+// Build returns a JSON Schema that describes dg config files.
+func Build() map[string]any {
+	definitions := map[string]any{}
+
+	for name, p := range processors {
+		definitions["processor_"+name] = fromType(reflect.TypeOf(p))
+	}
+
+	for name, s := range sources {
+		definitions["source_"+name] = fromType(reflect.TypeOf(s))
+	}
+
+	placeholders := generator.Placeholders()
+	definitions["placeholder"] = map[string]any{
+		"type":        "string",
+		"description": "A function placeholder that can be used in a gen value.",
+		"enum":        placeholders,
+	}
+
+	genValue := properties(definitions["processor_gen"])["value"].(map[string]any)
+	genValue["description"] = "A value containing zero or more function placeholders, e.g. ${first_name} ${last_name}."
+	genValue["examples"] = placeholders
+
+	table := fromType(reflect.TypeOf(model.Table{}))
+	table["required"] = []string{"name", "columns"}
+	properties(table)["columns"] = map[string]any{
+		"type":  "array",
+		"items": map[string]any{"$ref": "#/definitions/column"},
+	}
+	properties(table)["count"] = map[string]any{
+		"type":        []string{"integer", "string"},
+		"description": "The number of rows to generate, or an expression based on the row counts of other tables, e.g. customer * 3.",
+	}
+	definitions["table"] = table
+
+	column := fromType(reflect.TypeOf(model.Column{}))
+	column["required"] = []string{"name", "type"}
+	properties(column)["type"] = map[string]any{
+		"type": "string",
+		"enum": sortedKeys(processors),
+	}
+	column["allOf"] = conditionals("type", "processor", "processor_", processors)
+	definitions["column"] = column
+
+	input := fromType(reflect.TypeOf(model.Input{}))
+	input["required"] = []string{"name", "type", "source"}
+	properties(input)["type"] = map[string]any{
+		"type": "string",
+		"enum": sortedKeys(sources),
+	}
+	input["allOf"] = conditionals("type", "source", "source_", sources)
+	definitions["input"] = input
+
+	return map[string]any{
+		"$schema":     "http://json-schema.org/draft-07/schema#",
+		"title":       "dg config",
+		"description": "A config file describing the tables and inputs used to generate relational data.",
+		"type":        "object",
+		"properties": map[string]any{
+			"tables": map[string]any{
+				"type":  "array",
+				"items": map[string]any{"$ref": "#/definitions/table"},
+			},
+			"inputs": map[string]any{
+				"type":  "array",
+				"items": map[string]any{"$ref": "#/definitions/input"},
+			},
+		},
+		"additionalProperties": false,
+		"definitions":          definitions,
+	}
+}
+
+// conditionals returns a collection of if/then schemas that apply the
+// correct definition to a field, based on the value of a discriminator field.
+func conditionals(discriminator, field, prefix string, types map[string]any) []any {
+	return lo.Map(sortedKeys(types), func(name string, _ int) any {
+		return map[string]any{
+			"if": map[string]any{
+				"properties": map[string]any{
+					discriminator: map[string]any{"const": name},
+				},
+			},
+			"then": map[string]any{
+				"properties": map[string]any{
+					field: map[string]any{"$ref": "#/definitions/" + prefix + name},
+				},
+			},
+		}
+	})
+}
+
+func fromType(t reflect.Type) map[string]any {
+	if t == rawMessageType {
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		// YAML will happily decode numbers and booleans into string fields,
+		// so string fields accept any scalar value.
+		return map[string]any{"type": []string{"string", "number", "boolean"}}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{
+			"type":  "array",
+			"items": fromType(t.Elem()),
+		}
+	case reflect.Map:
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": fromType(t.Elem()),
+		}
+	case reflect.Pointer:
+		return fromType(t.Elem())
+	case reflect.Struct:
+		return fromStruct(t)
+	default:
+		return map[string]any{}
+	}
+}
+
+func fromStruct(t reflect.Type) map[string]any {
+	props := map[string]any{}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" || name == "" {
+			continue
+		}
+
+		props[name] = fromType(f.Type)
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+}
+
+func properties(schema any) map[string]any {
+	return schema.(map[string]any)["properties"].(map[string]any)
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := lo.Keys(m)
+	sort.Strings(keys)
+
+	return keys
+}
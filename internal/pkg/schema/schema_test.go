@@ -0,0 +1,78 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	b, err := Generate()
+	assert.NoError(t, err)
+
+	var s map[string]any
+	assert.NoError(t, json.Unmarshal(b, &s))
+
+	definitions := s["definitions"].(map[string]any)
+	for name := range processors {
+		assert.Contains(t, definitions, "processor_"+name)
+	}
+	for name := range sources {
+		assert.Contains(t, definitions, "source_"+name)
+	}
+
+	placeholders := definitions["placeholder"].(map[string]any)["enum"].([]any)
+	assert.Contains(t, placeholders, "${uuid}")
+
+	column := definitions["column"].(map[string]any)
+	assert.Len(t, column["allOf"], len(processors))
+}
+
+func TestFromType(t *testing.T) {
+	type nested struct {
+		Value string `yaml:"value"`
+	}
+
+	type test struct {
+		Name       string         `yaml:"name"`
+		Count      int            `yaml:"count"`
+		Ratio      float64        `yaml:"ratio"`
+		Enabled    bool           `yaml:"enabled"`
+		Values     []string       `yaml:"values"`
+		Lookup     map[string]int `yaml:"lookup"`
+		Nested     nested         `yaml:"nested"`
+		Ignored    string         `yaml:"-"`
+		NoTag      string
+		unexported string
+	}
+
+	exp := map[string]any{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]any{
+			"name":    map[string]any{"type": []string{"string", "number", "boolean"}},
+			"count":   map[string]any{"type": "integer"},
+			"ratio":   map[string]any{"type": "number"},
+			"enabled": map[string]any{"type": "boolean"},
+			"values": map[string]any{
+				"type":  "array",
+				"items": map[string]any{"type": []string{"string", "number", "boolean"}},
+			},
+			"lookup": map[string]any{
+				"type":                 "object",
+				"additionalProperties": map[string]any{"type": "integer"},
+			},
+			"nested": map[string]any{
+				"type":                 "object",
+				"additionalProperties": false,
+				"properties": map[string]any{
+					"value": map[string]any{"type": []string{"string", "number", "boolean"}},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, exp, fromType(reflect.TypeOf(test{})))
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/samber/lo"
+)
+
+// WriteTable writes a header and rows as a bordered table, with each column
+// padded to the width of its widest value. Values wider than maxCellWidth
+// are truncated.
+func WriteTable(w io.Writer, header []string, rows [][]string, maxCellWidth int) error {
+	widths := make([]int, len(header))
+	for i, h := range header {
+		widths[i] = utf8.RuneCountInString(truncate(h, maxCellWidth))
+	}
+
+	for _, row := range rows {
+		for i := range header {
+			widths[i] = lo.Max([]int{widths[i], utf8.RuneCountInString(truncate(cell(row, i), maxCellWidth))})
+		}
+	}
+
+	var sb strings.Builder
+
+	border := func() {
+		sb.WriteString("+")
+		for _, width := range widths {
+			sb.WriteString(strings.Repeat("-", width+2))
+			sb.WriteString("+")
+		}
+		sb.WriteString("\n")
+	}
+
+	line := func(values func(int) string) {
+		sb.WriteString("|")
+		for i, width := range widths {
+			v := truncate(values(i), maxCellWidth)
+			fmt.Fprintf(&sb, " %s%s |", v, strings.Repeat(" ", width-utf8.RuneCountInString(v)))
+		}
+		sb.WriteString("\n")
+	}
+
+	border()
+	line(func(i int) string { return header[i] })
+	border()
+	for _, row := range rows {
+		line(func(i int) string { return cell(row, i) })
+	}
+	border()
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func cell(row []string, i int) string {
+	if i < len(row) {
+		return row[i]
+	}
+	return ""
+}
+
+func truncate(s string, maxWidth int) string {
+	// Keep each row on a single line.
+	s = strings.NewReplacer("\r", "", "\n", " ", "\t", " ").Replace(s)
+
+	if maxWidth <= 0 || utf8.RuneCountInString(s) <= maxWidth {
+		return s
+	}
+
+	if maxWidth <= 3 {
+		return string([]rune(s)[:maxWidth])
+	}
+	return string([]rune(s)[:maxWidth-3]) + "..."
+}
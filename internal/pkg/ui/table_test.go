@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteTable(t *testing.T) {
+	cases := []struct {
+		name         string
+		header       []string
+		rows         [][]string
+		maxCellWidth int
+		exp          string
+	}{
+		{
+			name:   "aligned columns",
+			header: []string{"id", "name"},
+			rows: [][]string{
+				{"1", "Alice"},
+				{"10", "Bob"},
+			},
+			exp: `+----+-------+
+| id | name  |
++----+-------+
+| 1  | Alice |
+| 10 | Bob   |
++----+-------+
+`,
+		},
+		{
+			name:   "no rows",
+			header: []string{"id"},
+			exp: `+----+
+| id |
++----+
++----+
+`,
+		},
+		{
+			name:   "multi-byte characters",
+			header: []string{"city"},
+			rows:   [][]string{{"Zürich"}},
+			exp: `+--------+
+| city   |
++--------+
+| Zürich |
++--------+
+`,
+		},
+		{
+			name:         "truncated values",
+			header:       []string{"description"},
+			rows:         [][]string{{"a very long\nvalue"}},
+			maxCellWidth: 10,
+			exp: `+------------+
+| descrip... |
++------------+
+| a very ... |
++------------+
+`,
+		},
+		{
+			name:   "short rows",
+			header: []string{"a", "b"},
+			rows:   [][]string{{"1"}},
+			exp: `+---+---+
+| a | b |
++---+---+
+| 1 |   |
++---+---+
+`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			assert.NoError(t, WriteTable(buf, c.header, c.rows, c.maxCellWidth))
+			assert.Equal(t, c.exp, buf.String())
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package unique
+
+import (
+	"fmt"
+
+	"github.com/codingconcepts/dg/internal/pkg/generator"
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/samber/lo"
+)
+
+// ColumnsGenerator generates the columns of a table that include returns
+// true for.
+type ColumnsGenerator func(t model.Table, files map[string]model.CSVFile, include func(model.Column) bool) error
+
+// positionalColumnTypes are the types of column whose values depend on the
+// position of their row, and therefore can't be regenerated for a subset of
+// rows.
+var positionalColumnTypes = []string{"each", "const", "inc", "range", "tree", "walk"}
+
+// maxStalledRegenerations is the number of times duplicate rows will be
+// regenerated without reducing the number of duplicates, before assuming
+// that there aren't enough unique combinations of values.
+const maxStalledRegenerations = 100
+
+// Drop removes the rows of a table that duplicate the unique_columns of an
+// earlier row, and returns the number of rows removed.
+func Drop(t model.Table, files map[string]model.CSVFile) int {
+	file := files[t.Name]
+	rows := file.RowCount()
+
+	file.Lines = generator.Transpose(file.Lines)
+	file.Lines = file.Unique()
+	file.Lines = generator.Transpose(file.Lines)
+	files[t.Name] = file
+
+	return rows - file.RowCount()
+}
+
+// Regenerate replaces the values of a table's rows that duplicate the
+// unique_columns of an earlier row, by regenerating the values of their
+// columns until every row is unique. Columns that can't be regenerated for a
+// subset of rows keep their values. It returns the number of rows replaced,
+// and the number of duplicates discarded in total.
+func Regenerate(t model.Table, files map[string]model.CSVFile, generate ColumnsGenerator) (int, int, error) {
+	regenerated := lo.FlatMap(t.Columns, func(c model.Column, _ int) []string {
+		if fixed(c) {
+			return nil
+		}
+		return generator.ColumnNames(c)
+	})
+
+	discarded, stalled := 0, 0
+	previous := -1
+	replaced := map[int]struct{}{}
+
+	for {
+		file := files[t.Name]
+
+		duplicates := file.DuplicateRows()
+		if len(duplicates) == 0 {
+			break
+		}
+
+		if !lo.Some(file.UniqueColumns, regenerated) {
+			return 0, 0, fmt.Errorf("%d rows of %q are duplicates, and none of its unique_columns can be regenerated", len(duplicates), t.Name)
+		}
+
+		if previous != -1 && len(duplicates) >= previous {
+			if stalled++; stalled == maxStalledRegenerations {
+				return 0, 0, fmt.Errorf("unable to generate %d unique rows for %q, as %d rows are still duplicates after %d attempts without progress", file.RowCount(), t.Name, len(duplicates), maxStalledRegenerations)
+			}
+		} else {
+			stalled = 0
+		}
+		previous = len(duplicates)
+		discarded += len(duplicates)
+		for _, row := range duplicates {
+			replaced[row] = struct{}{}
+		}
+
+		// Generate replacement values for the duplicate rows in a table of
+		// their own, which starts with the values of the columns that keep
+		// their values, so that generators can refer to them.
+		replacements := lo.Assign(files)
+		delete(replacements, t.Name)
+
+		subset := t
+		subset.Count = len(duplicates)
+
+		for i, name := range file.Header {
+			if lo.Contains(regenerated, name) {
+				continue
+			}
+
+			values := lo.Map(duplicates, func(row, _ int) string {
+				return file.Lines[i][row]
+			})
+			generator.AddTable(subset, name, values, replacements)
+		}
+
+		include := func(c model.Column) bool {
+			return lo.Contains(regenerated, c.Name)
+		}
+		if err := generate(subset, replacements, include); err != nil {
+			return 0, 0, err
+		}
+
+		replacement := replacements[t.Name]
+		for _, name := range regenerated {
+			src := lo.IndexOf(replacement.Header, name)
+			dst := lo.IndexOf(file.Header, name)
+
+			for i, row := range duplicates {
+				file.Lines[dst][row] = replacement.Lines[src][i]
+			}
+		}
+	}
+
+	return len(replaced), discarded, nil
+}
+
+// fixed returns true for columns that can't be regenerated for a subset of
+// rows, either because their values depend on the position of their row, or
+// because their values are constrained across the whole column (e.g. unique
+// gen columns, or refs that must cover every parent), which regenerating
+// some of their rows could break.
+func fixed(c model.Column) bool {
+	if lo.Contains(positionalColumnTypes, c.Type) {
+		return true
+	}
+
+	if c.Generator.UnmarshalFunc == nil {
+		return false
+	}
+
+	switch c.Type {
+	case "gen":
+		var g generator.GenGenerator
+		if err := c.Generator.UnmarshalFunc(&g); err != nil {
+			return false
+		}
+		return g.Unique
+
+	case "ref":
+		var g generator.RefGenerator
+		if err := c.Generator.UnmarshalFunc(&g); err != nil {
+			return false
+		}
+		return g.Distinct || g.Coverage != "" || g.MinPerParent > 0 || g.MaxPerParent > 0
+
+	case "timeseries":
+		var g generator.TimeseriesGenerator
+		if err := c.Generator.UnmarshalFunc(&g); err != nil {
+			return false
+		}
+		return g.Sort
+
+	case "case":
+		var g generator.CaseGenerator
+		if err := c.Generator.UnmarshalFunc(&g); err != nil {
+			return false
+		}
+
+		branches := g.When
+		if g.Else != nil {
+			branches = append(branches[:len(branches):len(branches)], *g.Else)
+		}
+		return lo.SomeBy(branches, func(b generator.CaseBranch) bool {
+			return fixed(model.Column{Name: c.Name, Type: b.Type, Generator: b.Processor})
+		})
+
+	default:
+		return false
+	}
+}
@@ -0,0 +1,308 @@
+package unique
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/codingconcepts/dg/internal/pkg/generator"
+	"github.com/codingconcepts/dg/internal/pkg/model"
+	"github.com/codingconcepts/dg/internal/pkg/random"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+// generate is a cut-down version of the column generation that dg performs.
+func generate(t model.Table, files map[string]model.CSVFile, include func(model.Column) bool) error {
+	for _, c := range t.Columns {
+		if !include(c) {
+			continue
+		}
+
+		if c.Type == "const" {
+			var g generator.ConstGenerator
+			if err := g.Generate(model.Table{Name: t.Name, Count: t.Count, Columns: []model.Column{c}}, files); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var g interface {
+			Generate(model.Table, model.Column, map[string]model.CSVFile) error
+		}
+
+		switch c.Type {
+		case "gen":
+			g = &generator.GenGenerator{}
+		case "set":
+			g = &generator.SetGenerator{}
+		case "ref":
+			g = &generator.RefGenerator{}
+		case "timeseries":
+			g = &generator.TimeseriesGenerator{}
+		default:
+			return fmt.Errorf("unsupported column type %q", c.Type)
+		}
+
+		if err := c.Generator.UnmarshalFunc(g); err != nil {
+			return err
+		}
+		if err := g.Generate(t, c, files); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func loadTables(t *testing.T, config string) ([]model.Table, map[string]model.CSVFile) {
+	c, err := model.LoadConfig(strings.NewReader(config))
+	assert.NoError(t, err)
+
+	random.Seed(1)
+	gofakeit.Seed(1)
+
+	files := map[string]model.CSVFile{}
+	for _, table := range c.Tables {
+		err := generate(table, files, func(model.Column) bool { return true })
+		assert.NoError(t, err)
+	}
+
+	return c.Tables, files
+}
+
+func column(file model.CSVFile, name string) []string {
+	return append([]string{}, file.Lines[lo.IndexOf(file.Header, name)]...)
+}
+
+const parent = `
+  - name: parent
+    count: 5
+    columns:
+      - name: id
+        type: const
+        processor:
+          values: [a, b, c, d, e]
+`
+
+func TestDrop(t *testing.T) {
+	tables, files := loadTables(t, `
+tables:
+  - name: child
+    count: 10
+    unique_columns: [grade]
+    columns:
+      - name: grade
+        type: set
+        processor:
+          values: [a, b]
+`)
+
+	discarded := Drop(tables[0], files)
+	assert.Equal(t, 8, discarded)
+	assert.ElementsMatch(t, []string{"a", "b"}, files["child"].Lines[0])
+}
+
+func TestRegenerate(t *testing.T) {
+	cases := []struct {
+		name   string
+		config string
+
+		// fixed are the columns that must keep their values.
+		fixed  []string
+		expErr string
+	}{
+		{
+			name: "regenerates duplicate rows",
+			config: `
+tables:
+  - name: child
+    count: 20
+    unique_columns: [grade]
+    columns:
+      - name: grade
+        type: gen
+        processor:
+          pattern: '[a-e]{2}'
+`,
+		},
+		{
+			name: "keeps unique gen columns",
+			config: `
+tables:
+  - name: child
+    count: 20
+    unique_columns: [grade, code]
+    columns:
+      - name: code
+        type: gen
+        processor:
+          pattern: '[a-d]'
+          unique: true
+          null_percentage: 50
+      - name: grade
+        type: gen
+        processor:
+          pattern: '[a-z]{2}'
+`,
+			fixed: []string{"code"},
+		},
+		{
+			name: "keeps distinct refs",
+			config: `
+tables:` + parent + `
+  - name: child
+    count: 5
+    unique_columns: [grade]
+    columns:
+      - name: parent_id
+        type: ref
+        processor:
+          table: parent
+          column: id
+          distinct: true
+      - name: grade
+        type: gen
+        processor:
+          pattern: '[a-z]{2}'
+`,
+			fixed: []string{"parent_id"},
+		},
+		{
+			name: "keeps refs with coverage",
+			config: `
+tables:` + parent + `
+  - name: child
+    count: 20
+    unique_columns: [grade]
+    columns:
+      - name: parent_id
+        type: ref
+        processor:
+          table: parent
+          column: id
+          coverage: all
+          max_per_parent: 10
+      - name: grade
+        type: gen
+        processor:
+          pattern: '[a-z]{2}'
+`,
+			fixed: []string{"parent_id"},
+		},
+		{
+			name: "keeps sorted timeseries",
+			config: `
+tables:
+  - name: child
+    count: 20
+    unique_columns: [grade]
+    columns:
+      - name: at
+        type: timeseries
+        processor:
+          from: 2023-01-01T00:00:00Z
+          to: 2023-02-01T00:00:00Z
+          sort: true
+      - name: grade
+        type: gen
+        processor:
+          pattern: '[a-z]{2}'
+`,
+			fixed: []string{"at"},
+		},
+		{
+			name: "no unique columns can be regenerated",
+			config: `
+tables:
+  - name: child
+    count: 3
+    unique_columns: [code]
+    columns:
+      - name: code
+        type: const
+        processor:
+          values: [a]
+`,
+			expErr: `2 rows of "child" are duplicates, and none of its unique_columns can be regenerated`,
+		},
+		{
+			name: "stall limit",
+			config: `
+tables:
+  - name: child
+    count: 3
+    unique_columns: [grade]
+    columns:
+      - name: grade
+        type: set
+        processor:
+          values: [a, b]
+`,
+			expErr: `unable to generate 3 unique rows for "child", as 1 rows are still duplicates after 100 attempts without progress`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tables, files := loadTables(t, c.config)
+			table := tables[len(tables)-1]
+
+			before := lo.Map(c.fixed, func(name string, _ int) []string {
+				return column(files[table.Name], name)
+			})
+
+			_, _, err := Regenerate(table, files, generate)
+			if c.expErr != "" {
+				assert.EqualError(t, err, c.expErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			file := files[table.Name]
+			assert.Empty(t, file.DuplicateRows())
+			assert.Equal(t, table.Count, file.RowCount())
+
+			for i, name := range c.fixed {
+				assert.Equal(t, before[i], column(file, name))
+			}
+		})
+	}
+}
+
+func TestFixed(t *testing.T) {
+	cases := []struct {
+		name   string
+		column string
+		exp    bool
+	}{
+		{name: "positional", column: "type: inc\nprocessor:\n  start: 1", exp: true},
+		{name: "gen", column: "type: gen\nprocessor:\n  value: ${uuid}", exp: false},
+		{name: "unique gen", column: "type: gen\nprocessor:\n  value: ${uuid}\n  unique: true", exp: true},
+		{name: "ref", column: "type: ref\nprocessor:\n  table: a\n  column: id", exp: false},
+		{name: "distinct ref", column: "type: ref\nprocessor:\n  table: a\n  column: id\n  distinct: true", exp: true},
+		{name: "ref with coverage", column: "type: ref\nprocessor:\n  table: a\n  column: id\n  coverage: all", exp: true},
+		{name: "ref with min per parent", column: "type: ref\nprocessor:\n  table: a\n  column: id\n  min_per_parent: 1", exp: true},
+		{name: "ref with max per parent", column: "type: ref\nprocessor:\n  table: a\n  column: id\n  max_per_parent: 1", exp: true},
+		{name: "timeseries", column: "type: timeseries\nprocessor:\n  from: 2023-01-01T00:00:00Z", exp: false},
+		{name: "sorted timeseries", column: "type: timeseries\nprocessor:\n  sort: true", exp: true},
+		{name: "case", column: "type: case\nprocessor:\n  when:\n    - column: a\n      type: set\n      processor:\n        values: [a]", exp: false},
+		{name: "case with fixed branch", column: "type: case\nprocessor:\n  when:\n    - column: a\n      type: set\n      processor:\n        values: [a]\n  else:\n    type: ref\n    processor:\n      distinct: true", exp: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config := "tables:\n  - name: t\n    columns:\n      - name: c\n" + indent(c.column, "        ")
+
+			cfg, err := model.LoadConfig(strings.NewReader(config))
+			assert.NoError(t, err)
+			assert.Equal(t, c.exp, fixed(cfg.Tables[0].Columns[0]))
+		})
+	}
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	return prefix + strings.Join(lines, "\n"+prefix) + "\n"
+}